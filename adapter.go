@@ -80,6 +80,8 @@ func NewAdapter(ctx context.Context, options *options.AdapterOptions) AdapterGro
 		return aliyun.NewAdapter(ctx, options)
 	case consts.PROVIDER_XFYUN:
 		return xfyun.NewAdapter(ctx, options)
+	case consts.PROVIDER_XFYUN_HTTP:
+		return xfyun.NewHttpAdapter(ctx, options)
 	case consts.PROVIDER_ZHIPUAI:
 		return zhipuai.NewAdapter(ctx, options)
 	case consts.PROVIDER_VOLC_ENGINE:
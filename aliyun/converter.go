@@ -34,12 +34,19 @@ func (a *Aliyun) ConvChatCompletionsRequest(ctx context.Context, data any) (requ
 		}
 	}
 
+	isSupportSystemRole := true
 	if a.IsSupportSystemRole != nil {
-		request.Messages = common.HandleMessages(request.Messages, *a.IsSupportSystemRole)
-	} else {
-		request.Messages = common.HandleMessages(request.Messages, true)
+		isSupportSystemRole = *a.IsSupportSystemRole
 	}
 
+	// 通义千问要求以user开始且user/assistant交替
+	request.Messages = common.HandleMessagesWithOptions(request.Messages, common.MessagesOptions{
+		IsSupportSystemRole: isSupportSystemRole,
+		IsMergeSameRole:     true,
+		IsUserFirst:         true,
+		IsStrictAlternation: true,
+	})
+
 	return request, nil
 }
 
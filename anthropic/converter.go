@@ -34,12 +34,19 @@ func (a *Anthropic) ConvChatCompletionsRequest(ctx context.Context, data any) (r
 		}
 	}
 
+	isSupportSystemRole := true
 	if a.IsSupportSystemRole != nil {
-		request.Messages = common.HandleMessages(request.Messages, *a.IsSupportSystemRole)
-	} else {
-		request.Messages = common.HandleMessages(request.Messages, true)
+		isSupportSystemRole = *a.IsSupportSystemRole
 	}
 
+	// Claude要求以user开始且user/assistant交替
+	request.Messages = common.HandleMessagesWithOptions(request.Messages, common.MessagesOptions{
+		IsSupportSystemRole: isSupportSystemRole,
+		IsMergeSameRole:     true,
+		IsUserFirst:         true,
+		IsStrictAlternation: true,
+	})
+
 	return request, nil
 }
 
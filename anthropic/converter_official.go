@@ -32,7 +32,7 @@ func (a *Anthropic) ConvChatCompletionsRequestOfficial(ctx context.Context, requ
 		Tools:         request.Tools,
 	}
 
-	if chatCompletionReq.Messages[0].Role == consts.ROLE_SYSTEM {
+	if len(chatCompletionReq.Messages) > 0 && chatCompletionReq.Messages[0].Role == consts.ROLE_SYSTEM {
 		chatCompletionReq.System = chatCompletionReq.Messages[0].Content
 		chatCompletionReq.Messages = chatCompletionReq.Messages[1:]
 	}
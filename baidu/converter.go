@@ -34,12 +34,19 @@ func (b *Baidu) ConvChatCompletionsRequest(ctx context.Context, data any) (reque
 		}
 	}
 
+	isSupportSystemRole := true
 	if b.IsSupportSystemRole != nil {
-		request.Messages = common.HandleMessages(request.Messages, *b.IsSupportSystemRole)
-	} else {
-		request.Messages = common.HandleMessages(request.Messages, true)
+		isSupportSystemRole = *b.IsSupportSystemRole
 	}
 
+	// 文心要求以user开始且user/assistant交替
+	request.Messages = common.HandleMessagesWithOptions(request.Messages, common.MessagesOptions{
+		IsSupportSystemRole: isSupportSystemRole,
+		IsMergeSameRole:     true,
+		IsUserFirst:         true,
+		IsStrictAlternation: true,
+	})
+
 	if len(request.Messages) == 1 && request.Messages[0].Role == consts.ROLE_SYSTEM {
 		request.Messages[0].Role = consts.ROLE_USER
 	}
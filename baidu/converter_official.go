@@ -29,7 +29,7 @@ func (b *Baidu) ConvChatCompletionsRequestOfficial(ctx context.Context, request
 		UserId:          request.User,
 	}
 
-	if chatCompletionReq.Messages[0].Role == consts.ROLE_SYSTEM {
+	if len(chatCompletionReq.Messages) > 0 && chatCompletionReq.Messages[0].Role == consts.ROLE_SYSTEM {
 		chatCompletionReq.System = gconv.String(chatCompletionReq.Messages[0].Content)
 		chatCompletionReq.Messages = chatCompletionReq.Messages[1:]
 	}
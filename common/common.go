@@ -4,42 +4,69 @@ import (
 	"fmt"
 
 	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/iimeta/fastapi-sdk/v2/consts"
 	"github.com/iimeta/fastapi-sdk/v2/model"
 )
 
-func HandleMessages(messages []model.ChatCompletionMessage, isSupportSystemRole bool) []model.ChatCompletionMessage {
+// 消息处理选项, 按各供应商的要求配置
+type MessagesOptions struct {
+	IsSupportSystemRole    bool // 是否支持system角色, 不支持时转为user消息
+	IsSupportDeveloperRole bool // 是否支持developer角色, 不支持时转为system角色
+	IsMergeSameRole        bool // 是否合并连续相同角色的消息, 用于要求user/assistant交替的模型
+	IsUserFirst            bool // 是否要求system之后的首条消息为user, 之前的消息将被丢弃
+	IsStrictAlternation    bool // 是否要求user/assistant严格交替, 工具结果视为user一侧
+}
+
+// 处理消息, 保留tool_calls与工具结果的配对, 不会调整消息顺序
+func HandleMessagesWithOptions(messages []model.ChatCompletionMessage, options MessagesOptions) []model.ChatCompletionMessage {
 
-	var (
-		newMessages       = make([]model.ChatCompletionMessage, 0)
-		systemRoleMessage *model.ChatCompletionMessage
-	)
+	newMessages := make([]model.ChatCompletionMessage, 0, len(messages))
 
 	for _, message := range messages {
-		if message.Content != "" {
-			newMessages = append(newMessages, message)
+
+		// 仅去除既没有内容也没有工具调用的消息, 工具结果即使为空也需保留
+		if isEmptyContent(message.Content) && !isToolMessage(message) {
+			continue
 		}
-	}
 
-	if isSupportSystemRole && newMessages[0].Role == consts.ROLE_SYSTEM {
-		systemRoleMessage = &newMessages[0]
-		newMessages = newMessages[1:]
-	}
+		if message.Role == consts.ROLE_DEVELOPER && !options.IsSupportDeveloperRole {
+			message.Role = consts.ROLE_SYSTEM
+		}
 
-	if len(newMessages) != 0 && len(newMessages)%2 == 0 {
-		newMessages = newMessages[1:]
-	}
+		if message.Role == consts.ROLE_SYSTEM && !options.IsSupportSystemRole {
+			message.Role = consts.ROLE_USER
+		}
+
+		if options.IsMergeSameRole && len(newMessages) > 0 {
+
+			prev := &newMessages[len(newMessages)-1]
+
+			if prev.Role == message.Role && canMergeMessage(*prev, message) {
 
-	for i := len(newMessages) - 1; i >= 0; i-- {
-		if i%2 == 0 {
-			newMessages[i].Role = consts.ROLE_USER
-		} else {
-			newMessages[i].Role = consts.ROLE_ASSISTANT
+				prev.Content = mergeContent(prev.Content, message.Content)
+
+				if message.ToolCalls != nil {
+					prev.ToolCalls = message.ToolCalls
+				}
+
+				if message.FunctionCall != nil {
+					prev.FunctionCall = message.FunctionCall
+				}
+
+				continue
+			}
 		}
+
+		newMessages = append(newMessages, message)
+	}
+
+	if options.IsUserFirst {
+		newMessages = userFirst(newMessages)
 	}
 
-	if systemRoleMessage != nil {
-		newMessages = append([]model.ChatCompletionMessage{*systemRoleMessage}, newMessages...)
+	if options.IsStrictAlternation {
+		newMessages = strictAlternation(newMessages)
 	}
 
 	return newMessages
@@ -80,3 +107,153 @@ func GetMime(url string) (mimeType string, data string) {
 
 	return mimeType, data
 }
+
+func isToolMessage(message model.ChatCompletionMessage) bool {
+	return message.Role == consts.ROLE_TOOL || message.Role == consts.ROLE_FUNCTION || message.ToolCalls != nil || message.FunctionCall != nil
+}
+
+// 工具结果需与tool_call_id一一对应, 不能合并; 已带工具调用的assistant消息之后不能再追加内容
+func canMergeMessage(prev, message model.ChatCompletionMessage) bool {
+
+	if prev.Role == consts.ROLE_TOOL || prev.Role == consts.ROLE_FUNCTION {
+		return false
+	}
+
+	return prev.ToolCalls == nil && prev.FunctionCall == nil
+}
+
+func isEmptyContent(content any) bool {
+
+	switch v := content.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+// 合并消息内容, 均为文本时换行拼接, 否则转为多模态内容数组
+func mergeContent(a, b any) any {
+
+	if isEmptyContent(a) {
+		return b
+	}
+
+	if isEmptyContent(b) {
+		return a
+	}
+
+	textA, okA := a.(string)
+	textB, okB := b.(string)
+
+	if okA && okB {
+		return textA + "\n\n" + textB
+	}
+
+	return append(toContentParts(a), toContentParts(b)...)
+}
+
+func toContentParts(content any) []any {
+
+	if parts, ok := content.([]any); ok {
+		return parts
+	}
+
+	return []any{map[string]any{
+		"type": "text",
+		"text": gconv.String(content),
+	}}
+}
+
+// 丢弃system之后、首条user消息之前的消息
+func userFirst(messages []model.ChatCompletionMessage) []model.ChatCompletionMessage {
+
+	newMessages := make([]model.ChatCompletionMessage, 0, len(messages))
+	isFound := false
+
+	for _, message := range messages {
+
+		if !isFound && message.Role != consts.ROLE_SYSTEM {
+			if message.Role != consts.ROLE_USER {
+				continue
+			}
+			isFound = true
+		}
+
+		newMessages = append(newMessages, message)
+	}
+
+	return newMessages
+}
+
+// 保证user/assistant交替: 没有工具结果的tool_calls和没有对应调用的工具结果按普通消息处理, 相邻同侧的普通消息合并
+func strictAlternation(messages []model.ChatCompletionMessage) []model.ChatCompletionMessage {
+
+	newMessages := make([]model.ChatCompletionMessage, 0, len(messages))
+
+	for i, message := range messages {
+
+		if message.Role == consts.ROLE_ASSISTANT && (message.ToolCalls != nil || message.FunctionCall != nil) {
+			if i+1 >= len(messages) || !isToolResult(messages[i+1]) {
+				message.ToolCalls = nil
+				message.FunctionCall = nil
+			}
+		}
+
+		if isToolResult(message) && !hasPrecedingToolCall(newMessages) {
+			message = model.ChatCompletionMessage{
+				Role:    consts.ROLE_USER,
+				Content: message.Content,
+			}
+		}
+
+		// 去除工具调用后没有内容的消息直接丢弃, 前后同侧的消息会在后续合并
+		if isEmptyContent(message.Content) && !isToolMessage(message) {
+			continue
+		}
+
+		if len(newMessages) > 0 {
+
+			prev := &newMessages[len(newMessages)-1]
+
+			if prev.Role != consts.ROLE_SYSTEM && prev.Role == message.Role && canMergeMessage(*prev, message) && !isToolResult(message) {
+
+				prev.Content = mergeContent(prev.Content, message.Content)
+
+				if message.ToolCalls != nil {
+					prev.ToolCalls = message.ToolCalls
+				}
+
+				if message.FunctionCall != nil {
+					prev.FunctionCall = message.FunctionCall
+				}
+
+				continue
+			}
+		}
+
+		newMessages = append(newMessages, message)
+	}
+
+	return newMessages
+}
+
+func isToolResult(message model.ChatCompletionMessage) bool {
+	return message.Role == consts.ROLE_TOOL || message.Role == consts.ROLE_FUNCTION
+}
+
+// 上一条消息为工具调用或工具结果时, 当前工具结果才有对应的调用
+func hasPrecedingToolCall(messages []model.ChatCompletionMessage) bool {
+
+	if len(messages) == 0 {
+		return false
+	}
+
+	prev := messages[len(messages)-1]
+
+	return isToolResult(prev) || prev.ToolCalls != nil || prev.FunctionCall != nil
+}
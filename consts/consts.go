@@ -10,6 +10,7 @@ const (
 	PROVIDER_BAIDU          = "Baidu"
 	PROVIDER_ALIYUN         = "Aliyun"
 	PROVIDER_XFYUN          = "Xfyun"
+	PROVIDER_XFYUN_HTTP     = "Xfyun-HTTP"
 	PROVIDER_ZHIPUAI        = "ZhipuAI"
 	PROVIDER_VOLC_ENGINE    = "VolcEngine"
 	PROVIDER_AWS_CLAUDE     = "AWSClaude"
@@ -64,3 +65,30 @@ const (
 	FinishReasonContentFilter = "content_filter"
 	FinishReasonNull          = "null"
 )
+
+const (
+	REALTIME_EVENT_SESSION_UPDATE                        = "session.update"
+	REALTIME_EVENT_INPUT_AUDIO_BUFFER_APPEND             = "input_audio_buffer.append"
+	REALTIME_EVENT_INPUT_AUDIO_BUFFER_COMMIT             = "input_audio_buffer.commit"
+	REALTIME_EVENT_INPUT_AUDIO_BUFFER_CLEAR              = "input_audio_buffer.clear"
+	REALTIME_EVENT_CONVERSATION_ITEM_CREATE              = "conversation.item.create"
+	REALTIME_EVENT_CONVERSATION_ITEM_TRUNCATE            = "conversation.item.truncate"
+	REALTIME_EVENT_CONVERSATION_ITEM_DELETE              = "conversation.item.delete"
+	REALTIME_EVENT_RESPONSE_CREATE                       = "response.create"
+	REALTIME_EVENT_RESPONSE_CANCEL                       = "response.cancel"
+	REALTIME_EVENT_ERROR                                 = "error"
+	REALTIME_EVENT_SESSION_CREATED                       = "session.created"
+	REALTIME_EVENT_SESSION_UPDATED                       = "session.updated"
+	REALTIME_EVENT_INPUT_AUDIO_BUFFER_COMMITTED          = "input_audio_buffer.committed"
+	REALTIME_EVENT_INPUT_AUDIO_BUFFER_CLEARED            = "input_audio_buffer.cleared"
+	REALTIME_EVENT_INPUT_AUDIO_BUFFER_SPEECH_STARTED     = "input_audio_buffer.speech_started"
+	REALTIME_EVENT_INPUT_AUDIO_BUFFER_SPEECH_STOPPED     = "input_audio_buffer.speech_stopped"
+	REALTIME_EVENT_CONVERSATION_ITEM_CREATED             = "conversation.item.created"
+	REALTIME_EVENT_RESPONSE_CREATED                      = "response.created"
+	REALTIME_EVENT_RESPONSE_DONE                         = "response.done"
+	REALTIME_EVENT_RESPONSE_TEXT_DELTA                   = "response.text.delta"
+	REALTIME_EVENT_RESPONSE_AUDIO_DELTA                  = "response.audio.delta"
+	REALTIME_EVENT_RESPONSE_AUDIO_TRANSCRIPT_DELTA       = "response.audio_transcript.delta"
+	REALTIME_EVENT_RESPONSE_FUNCTION_CALL_ARGUMENTS_DONE = "response.function_call_arguments.done"
+	REALTIME_EVENT_INPUT_AUDIO_TRANSCRIPTION_DELTA       = "conversation.item.input_audio_transcription.delta"
+)
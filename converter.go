@@ -66,6 +66,23 @@ type Converter interface {
 	ConvBatchResponse(ctx context.Context, data []byte) (response model.BatchResponse, err error)
 }
 
+// StreamConverter 流式响应转换, 需跨分片缓存状态的服务商每个流使用独立实例
+type StreamConverter interface {
+	ConvChatCompletionsStreamResponseOfficial(ctx context.Context, response model.ChatCompletionResponse) ([]byte, error)
+}
+
+// NewStreamConverter 每个流创建一个, 如: 转换为Gemini格式时需拼接被拆分到多个分片的工具调用参数
+func NewStreamConverter(ctx context.Context, options *options.AdapterOptions) StreamConverter {
+
+	converter := NewConverter(ctx, options)
+
+	if google, ok := converter.(*google.Google); ok {
+		return google.NewStreamConverter()
+	}
+
+	return converter
+}
+
 func NewConverter(ctx context.Context, options *options.AdapterOptions) Converter {
 
 	logger.Infof(ctx, "NewConverter provider: %s", options.Provider)
@@ -89,6 +106,8 @@ func NewConverter(ctx context.Context, options *options.AdapterOptions) Converte
 		return &aliyun.Aliyun{AdapterOptions: options}
 	case consts.PROVIDER_XFYUN:
 		return &xfyun.Xfyun{AdapterOptions: options}
+	case consts.PROVIDER_XFYUN_HTTP:
+		return xfyun.NewHttpConverter(options)
 	case consts.PROVIDER_ZHIPUAI:
 		return &zhipuai.ZhipuAI{AdapterOptions: options}
 	case consts.PROVIDER_VOLC_ENGINE:
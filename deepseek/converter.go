@@ -35,8 +35,12 @@ func (d *DeepSeek) ConvChatCompletionsRequest(ctx context.Context, data any) (re
 		}
 	}
 
+	// deepseek-reasoner不支持连续相同角色的消息
 	if d.IsSupportSystemRole != nil {
-		request.Messages = common.HandleMessages(request.Messages, *d.IsSupportSystemRole)
+		request.Messages = common.HandleMessagesWithOptions(request.Messages, common.MessagesOptions{
+			IsSupportSystemRole: *d.IsSupportSystemRole,
+			IsMergeSameRole:     true,
+		})
 	}
 
 	return request, nil
@@ -39,12 +39,19 @@ func (g *General) ConvChatCompletionsRequest(ctx context.Context, data any) (req
 		}
 	}
 
+	isSupportSystemRole := true
 	if g.IsSupportSystemRole != nil {
-		request.Messages = common.HandleMessages(request.Messages, *g.IsSupportSystemRole)
-	} else {
-		request.Messages = common.HandleMessages(request.Messages, true)
+		isSupportSystemRole = *g.IsSupportSystemRole
 	}
 
+	// 未知供应商沿用原有处理, 以user开始且user/assistant交替
+	request.Messages = common.HandleMessagesWithOptions(request.Messages, common.MessagesOptions{
+		IsSupportSystemRole: isSupportSystemRole,
+		IsMergeSameRole:     true,
+		IsUserFirst:         true,
+		IsStrictAlternation: true,
+	})
+
 	return request, nil
 }
 
@@ -2,16 +2,115 @@ package google
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (g *Google) AudioSpeech(ctx context.Context, data []byte) (response model.SpeechResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "AudioSpeech Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "AudioSpeech Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	// 透传请求时无法获取response_format, 默认返回WAV
+	responseFormat := ""
+
+	if !slices.Contains(g.ReqPassthroughParams, "req_data") {
+
+		request, err := g.ConvAudioSpeechRequest(ctx, data)
+		if err != nil {
+			logger.Errorf(ctx, "AudioSpeech Google ConvAudioSpeechRequest error: %v", err)
+			return response, err
+		}
+
+		// Gemini仅返回PCM音频, 可封装为WAV, 不支持转码为mp3等压缩格式
+		if request.ResponseFormat != "" && request.ResponseFormat != "wav" && request.ResponseFormat != "pcm" {
+			return response, fmt.Errorf("AudioSpeech Google model: %s, unsupported response_format: %s, only wav and pcm are supported", g.Model, request.ResponseFormat)
+		}
+
+		responseFormat = request.ResponseFormat
+
+		if data, err = g.ConvAudioSpeechRequestOfficial(ctx, request); err != nil {
+			logger.Errorf(ctx, "AudioSpeech Google ConvAudioSpeechRequestOfficial error: %v", err)
+			return response, err
+		}
+	}
+
+	if g.Path == "" {
+		g.Path = "/models/" + g.Model
+	}
+
+	if g.Action == "" {
+		g.Action = "generateContent"
+	}
+
+	bytes, responseHeader, err := util.HttpPost(ctx, fmt.Sprintf("%s%s:%s", g.BaseUrl, g.Path, g.Action), g.header, data, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "AudioSpeech Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.convAudioSpeechResponse(ctx, bytes, responseFormat); err != nil {
+		logger.Errorf(ctx, "AudioSpeech Google ConvAudioSpeechResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseBytes = bytes
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "AudioSpeech Google model: %s finished", g.Model)
+
+	return response, nil
 }
 
 func (g *Google) AudioTranscriptions(ctx context.Context, request model.AudioRequest) (response model.AudioResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "AudioTranscriptions Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "AudioTranscriptions Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	data, err := g.ConvAudioTranscriptionsRequest(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "AudioTranscriptions Google ConvAudioTranscriptionsRequest error: %v", err)
+		return response, err
+	}
+
+	if g.Path == "" {
+		g.Path = "/models/" + g.Model
+	}
+
+	if g.Action == "" {
+		g.Action = "generateContent"
+	}
+
+	bytes, responseHeader, err := util.HttpPost(ctx, fmt.Sprintf("%s%s:%s", g.BaseUrl, g.Path, g.Action), g.header, data.Bytes(), nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "AudioTranscriptions Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.convAudioTranscriptionsResponse(ctx, bytes, request.ResponseFormat); err != nil {
+		logger.Errorf(ctx, "AudioTranscriptions Google ConvAudioTranscriptionsResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseBytes = bytes
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "AudioTranscriptions Google model: %s finished", g.Model)
+
+	return response, nil
 }
@@ -2,26 +2,185 @@ package google
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"strings"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (g *Google) BatchCreate(ctx context.Context, request model.BatchCreateRequest) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchCreate Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchCreate Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	if g.isGcp {
+		return response, errors.New("GCPGemini does not support batch, please use Vertex AI batch prediction")
+	}
+
+	data, err := g.ConvBatchCreateRequest(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "BatchCreate Google ConvBatchCreateRequest error: %v", err)
+		return response, err
+	}
+
+	if g.Path == "" {
+		g.Path = "/models/" + g.Model
+	}
+
+	if g.Action == "" {
+		g.Action = "batchGenerateContent"
+	}
+
+	bytes, responseHeader, err := util.HttpPost(ctx, fmt.Sprintf("%s%s:%s", g.BaseUrl, g.Path, g.Action), g.header, data.Bytes(), nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "BatchCreate Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvBatchResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "BatchCreate Google ConvBatchResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseHeaders = responseHeader
+
+	if response.InputFileId == "" {
+		response.InputFileId = request.InputFileId
+	}
+
+	if response.Model == "" {
+		response.Model = g.Model
+	}
+
+	logger.Infof(ctx, "BatchCreate Google model: %s finished", g.Model)
+
+	return response, nil
 }
 
 func (g *Google) BatchList(ctx context.Context, request model.BatchListRequest) (response model.BatchListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchList Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchList Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	if g.Path == "" {
+		g.Path = "/batches"
+	}
+
+	query := url.Values{}
+
+	if request.Limit > 0 {
+		query.Set("pageSize", fmt.Sprint(request.Limit))
+	}
+
+	// Google使用nextPageToken分页, after传入上一页返回的last_id
+	if request.After != "" {
+		query.Set("pageToken", request.After)
+	}
+
+	rawURL := g.BaseUrl + g.Path
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+
+	bytes, _, err := util.HttpGet(ctx, rawURL, g.header, nil, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "BatchList Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvBatchListResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "BatchList Google ConvBatchListResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "BatchList Google model: %s finished", g.Model)
+
+	return response, nil
 }
 
 func (g *Google) BatchRetrieve(ctx context.Context, request model.BatchRetrieveRequest) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchRetrieve Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchRetrieve Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	if g.Path == "" {
+		g.Path = "/batches/" + strings.TrimPrefix(request.BatchId, "batches/")
+	}
+
+	bytes, responseHeader, err := util.HttpGet(ctx, g.BaseUrl+g.Path, g.header, nil, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "BatchRetrieve Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvBatchResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "BatchRetrieve Google ConvBatchResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "BatchRetrieve Google model: %s finished", g.Model)
+
+	return response, nil
 }
 
 func (g *Google) BatchCancel(ctx context.Context, request model.BatchCancelRequest) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchCancel Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchCancel Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	batchPath := "/batches/" + strings.TrimPrefix(request.BatchId, "batches/")
+
+	if g.Path == "" {
+		g.Path = batchPath + ":cancel"
+	}
+
+	if _, _, err = util.HttpPost(ctx, g.BaseUrl+g.Path, g.header, nil, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler); err != nil {
+		logger.Errorf(ctx, "BatchCancel Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	// 取消接口返回空对象, 重新获取任务状态
+	bytes, responseHeader, err := util.HttpGet(ctx, g.BaseUrl+batchPath, g.header, nil, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "BatchCancel Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvBatchResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "BatchCancel Google ConvBatchResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "BatchCancel Google model: %s finished", g.Model)
+
+	return response, nil
 }
@@ -0,0 +1,297 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
+	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
+)
+
+func (g *Google) CacheCreate(ctx context.Context, request model.CacheCreateRequest) (response model.CacheResponse, err error) {
+
+	logger.Infof(ctx, "CacheCreate Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "CacheCreate Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	data, err := g.ConvCacheCreateRequest(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "CacheCreate Google ConvCacheCreateRequest error: %v", err)
+		return response, err
+	}
+
+	cachedContentsPath, err := g.cachedContentsPath()
+	if err != nil {
+		logger.Errorf(ctx, "CacheCreate Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpPost(ctx, g.BaseUrl+cachedContentsPath, g.header, data, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "CacheCreate Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvCacheResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "CacheCreate Google ConvCacheResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "CacheCreate Google model: %s finished", g.Model)
+
+	return response, nil
+}
+
+func (g *Google) CacheList(ctx context.Context, request model.CacheListRequest) (response model.CacheListResponse, err error) {
+
+	logger.Infof(ctx, "CacheList Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "CacheList Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	query := url.Values{}
+
+	if request.Limit > 0 {
+		query.Set("pageSize", fmt.Sprint(request.Limit))
+	}
+
+	// Google使用nextPageToken分页, after传入上一页返回的last_id
+	if request.After != "" {
+		query.Set("pageToken", request.After)
+	}
+
+	cachedContentsPath, err := g.cachedContentsPath()
+	if err != nil {
+		logger.Errorf(ctx, "CacheList Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	rawURL := g.BaseUrl + cachedContentsPath
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+
+	bytes, _, err := util.HttpGet(ctx, rawURL, g.header, nil, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "CacheList Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvCacheListResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "CacheList Google ConvCacheListResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "CacheList Google model: %s finished", g.Model)
+
+	return response, nil
+}
+
+func (g *Google) CacheRetrieve(ctx context.Context, request model.CacheRetrieveRequest) (response model.CacheResponse, err error) {
+
+	logger.Infof(ctx, "CacheRetrieve Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "CacheRetrieve Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	cachedContentUrl, err := g.cachedContentUrl(request.CacheId)
+	if err != nil {
+		logger.Errorf(ctx, "CacheRetrieve Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpGet(ctx, cachedContentUrl, g.header, nil, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "CacheRetrieve Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvCacheResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "CacheRetrieve Google ConvCacheResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "CacheRetrieve Google model: %s finished", g.Model)
+
+	return response, nil
+}
+
+func (g *Google) CacheUpdate(ctx context.Context, request model.CacheUpdateRequest) (response model.CacheResponse, err error) {
+
+	logger.Infof(ctx, "CacheUpdate Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "CacheUpdate Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	// 未指定时Google会将ttl视为0s并立即过期
+	if request.Ttl <= 0 && request.ExpiresAt <= 0 {
+		return response, errors.New("Google cache update requires ttl or expires_at")
+	}
+
+	cachedContent := model.GoogleCachedContent{}
+	updateMask := "ttl"
+
+	if request.ExpiresAt > 0 {
+		expireTime := gtime.NewFromTimeStamp(request.ExpiresAt).Time
+		cachedContent.ExpireTime = &expireTime
+		updateMask = "expireTime"
+	} else {
+		cachedContent.Ttl = fmt.Sprintf("%ds", request.Ttl)
+	}
+
+	cachedContentUrl, err := g.cachedContentUrl(request.CacheId)
+	if err != nil {
+		logger.Errorf(ctx, "CacheUpdate Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	header := map[string]string{
+		"Content-Type": "application/json",
+	}
+
+	for k, v := range g.header {
+		header[k] = v
+	}
+
+	bytes, _, err := util.HttpDo(ctx, http.MethodPatch, cachedContentUrl+"?updateMask="+updateMask, header, cachedContent, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "CacheUpdate Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvCacheResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "CacheUpdate Google ConvCacheResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "CacheUpdate Google model: %s finished", g.Model)
+
+	return response, nil
+}
+
+func (g *Google) CacheDelete(ctx context.Context, request model.CacheDeleteRequest) (response model.CacheResponse, err error) {
+
+	logger.Infof(ctx, "CacheDelete Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "CacheDelete Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	cachedContentName, err := g.cachedContentName(request.CacheId)
+	if err != nil {
+		logger.Errorf(ctx, "CacheDelete Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpDelete(ctx, g.BaseUrl+"/"+cachedContentName, g.header, nil, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "CacheDelete Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	response = model.CacheResponse{
+		Id:            convCacheId(cachedContentName),
+		Object:        "cache",
+		Model:         g.Model,
+		Deleted:       true,
+		ResponseBytes: bytes,
+	}
+
+	logger.Infof(ctx, "CacheDelete Google model: %s finished", g.Model)
+
+	return response, nil
+}
+
+// 缓存资源路径, GCP的缓存位于项目和区域下, 从模型路径中截取, 如: /projects/{project}/locations/{location}/cachedContents
+func (g *Google) cachedContentsPath() (string, error) {
+
+	if !g.isGcp {
+		return "/cachedContents", nil
+	}
+
+	locationPath, err := g.gcpLocationPath()
+	if err != nil {
+		return "", err
+	}
+
+	return locationPath + "/cachedContents", nil
+}
+
+// GCP未配置项目和区域时无法确定缓存路径, 需在path中指定 /projects/{project}/locations/{location}
+func (g *Google) gcpLocationPath() (string, error) {
+
+	i := strings.Index(g.Path, "/projects/")
+	j := strings.Index(g.Path, "/locations/")
+
+	if i == -1 || j <= i+len("/projects/") {
+		return "", errors.New(fmt.Sprintf("GCPGemini cache requires path containing /projects/{project}/locations/{location}, path: %s", g.Path))
+	}
+
+	location, _, _ := strings.Cut(g.Path[j+len("/locations/"):], "/")
+	if location == "" {
+		return "", errors.New(fmt.Sprintf("GCPGemini cache requires path containing /projects/{project}/locations/{location}, path: %s", g.Path))
+	}
+
+	return g.Path[i:j] + "/locations/" + location, nil
+}
+
+// 缓存完整名称, 兼容只传id的情况
+func (g *Google) cachedContentName(cacheId string) (string, error) {
+
+	if strings.Contains(cacheId, "cachedContents/") {
+		return cacheId, nil
+	}
+
+	cachedContentsPath, err := g.cachedContentsPath()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(cachedContentsPath, "/") + "/" + cacheId, nil
+}
+
+func (g *Google) cachedContentUrl(cacheId string) (string, error) {
+
+	name, err := g.cachedContentName(cacheId)
+	if err != nil {
+		return "", err
+	}
+
+	return g.BaseUrl + "/" + name, nil
+}
+
+// 缓存对应的模型名称, GCP需使用完整的发布者模型路径
+func (g *Google) cachedContentModel() (string, error) {
+
+	if !g.isGcp {
+		return "models/" + g.Model, nil
+	}
+
+	locationPath, err := g.gcpLocationPath()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(locationPath, "/") + "/publishers/google/models/" + g.Model, nil
+}
@@ -136,7 +136,8 @@ func (g *Google) ChatCompletionsStream(ctx context.Context, data any) (responseC
 		}()
 
 		var (
-			usage *model.Usage
+			usage         *model.Usage
+			toolCallIndex int // 工具调用可能分布在多个分片中, 索引需在整个流中递增
 		)
 
 		for {
@@ -176,6 +177,8 @@ func (g *Google) ChatCompletionsStream(ctx context.Context, data any) (responseC
 				return
 			}
 
+			toolCallIndex = convStreamToolCalls(&response, toolCallIndex)
+
 			if response.Usage != nil {
 				usage = response.Usage
 			} else {
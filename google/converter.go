@@ -3,7 +3,14 @@ package google
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"path"
 	"reflect"
 	"strings"
 
@@ -11,10 +18,11 @@ import (
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/gogf/gf/v2/util/grand"
-	"github.com/iimeta/fastapi-sdk/v2/common"
 	"github.com/iimeta/fastapi-sdk/v2/consts"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/tiktoken"
 	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
@@ -39,12 +47,6 @@ func (g *Google) ConvChatCompletionsRequest(ctx context.Context, data any) (requ
 		}
 	}
 
-	if g.IsSupportSystemRole != nil {
-		request.Messages = common.HandleMessages(request.Messages, *g.IsSupportSystemRole)
-	} else {
-		request.Messages = common.HandleMessages(request.Messages, false)
-	}
-
 	return request, nil
 }
 
@@ -61,7 +63,7 @@ func (g *Google) ConvChatCompletionsResponse(ctx context.Context, data []byte) (
 		return response, err
 	}
 
-	if chatCompletionRes.Error.Code != 0 || (chatCompletionRes.Candidates[0].FinishReason != "STOP" && chatCompletionRes.Candidates[0].FinishReason != "MAX_TOKENS") {
+	if chatCompletionRes.Error.Code != 0 {
 		logger.Errorf(ctx, "ConvChatCompletionsResponse Google model: %s, chatCompletionRes: %s", g.Model, gjson.MustEncodeString(chatCompletionRes))
 
 		err = g.apiErrorHandler(&chatCompletionRes)
@@ -70,6 +72,10 @@ func (g *Google) ConvChatCompletionsResponse(ctx context.Context, data []byte) (
 		return response, err
 	}
 
+	if chatCompletionRes.UsageMetadata == nil {
+		chatCompletionRes.UsageMetadata = new(model.UsageMetadata)
+	}
+
 	response = model.ChatCompletionResponse{
 		Id:      consts.COMPLETION_ID_PREFIX + grand.S(29),
 		Object:  consts.COMPLETION_OBJECT,
@@ -86,40 +92,70 @@ func (g *Google) ConvChatCompletionsResponse(ctx context.Context, data []byte) (
 		ResponseBytes: data,
 	}
 
-	for i, part := range chatCompletionRes.Candidates[0].Content.Parts {
+	// 提示词被拦截时没有candidates, 返回content_filter
+	if chatCompletionRes.PromptFeedback != nil && (chatCompletionRes.PromptFeedback.BlockReason != "" || len(chatCompletionRes.PromptFeedback.SafetyRatings) > 0) {
+		if contentFilterResults := convSafetyRatings(chatCompletionRes.PromptFeedback.SafetyRatings, chatCompletionRes.PromptFeedback.BlockReason); contentFilterResults != nil {
+			response.PromptAnnotations = []model.PromptAnnotation{{
+				PromptIndex:          0,
+				ContentFilterResults: *contentFilterResults,
+			}}
+		}
+	}
+
+	if len(chatCompletionRes.Candidates) == 0 {
+
+		finishReason := consts.FinishReasonStop
+		if chatCompletionRes.PromptFeedback != nil && chatCompletionRes.PromptFeedback.BlockReason != "" {
+			finishReason = consts.FinishReasonContentFilter
+		}
+
+		response.Choices = append(response.Choices, model.ChatCompletionChoice{
+			Message: &model.ChatCompletionMessage{
+				Role: consts.ROLE_ASSISTANT,
+			},
+			FinishReason: finishReason,
+		})
+
+		return response, nil
+	}
+
+	for _, candidate := range chatCompletionRes.Candidates {
+
+		content, reasoningContent, toolCalls := g.convContentParts(candidate.Content.Parts, false)
 
 		message := &model.ChatCompletionMessage{
 			Role:    consts.ROLE_ASSISTANT,
-			Content: part.Text,
+			Content: content,
 		}
 
-		if part.FunctionCall != nil {
-			if functionCall, ok := part.FunctionCall.(map[string]any); ok {
-				message.ToolCalls = []any{
-					map[string]any{
-						"id":   "call_" + grand.S(24),
-						"type": "function",
-						"function": map[string]any{
-							"name":      functionCall["name"],
-							"arguments": gconv.String(functionCall["args"]),
-						},
-						"extra_content": map[string]any{
-							"google": map[string]any{
-								"thought_signature": part.ThoughtSignature,
-							},
-						},
-					},
-				}
+		if reasoningContent != "" {
+			message.ReasoningContent = reasoningContent
+		}
+
+		finishReason := convFinishReason(candidate.FinishReason)
+
+		if len(toolCalls) > 0 {
+
+			message.ToolCalls = toolCalls
+
+			if finishReason == consts.FinishReasonStop {
+				finishReason = consts.FinishReasonToolCalls
 			}
 		}
 
 		response.Choices = append(response.Choices, model.ChatCompletionChoice{
-			Index:        i,
-			Message:      message,
-			FinishReason: consts.FinishReasonStop,
+			Index:                len(response.Choices),
+			Message:              message,
+			LogProbs:             convLogprobsResult(candidate.LogprobsResult),
+			FinishReason:         finishReason,
+			ContentFilterResults: convSafetyRatings(candidate.SafetyRatings, ""),
 		})
 	}
 
+	// 命中上下文缓存的tokens
+	response.Usage.PromptTokensDetails.CachedTokens = chatCompletionRes.UsageMetadata.CachedContentTokenCount
+	response.Usage.InputTokensDetails.CachedTokens = chatCompletionRes.UsageMetadata.CachedContentTokenCount
+
 	for _, promptTokensDetail := range chatCompletionRes.UsageMetadata.PromptTokensDetails {
 
 		if promptTokensDetail.Modality == "TEXT" {
@@ -182,18 +218,63 @@ func (g *Google) ConvChatCompletionsStreamResponse(ctx context.Context, data []b
 
 	for _, candidate := range chatCompletionRes.Candidates {
 
+		content, reasoningContent, toolCalls := g.convContentParts(candidate.Content.Parts, true)
+
 		delta := &model.ChatCompletionStreamChoiceDelta{
 			Role: consts.ROLE_ASSISTANT,
 		}
 
-		if len(candidate.Content.Parts) > 0 {
-			delta.Content = candidate.Content.Parts[0].Text
+		// 包含非文本内容时, 多模态内容块放在content_parts中, content仅保留文本
+		if contentParts, ok := content.([]any); ok {
+			delta.Content = convContentText(contentParts)
+			delta.ContentParts = contentParts
+		} else {
+			delta.Content = gconv.String(content)
 		}
 
-		response.Choices = append(response.Choices, model.ChatCompletionChoice{
+		if reasoningContent != "" {
+			delta.ReasoningContent = reasoningContent
+		}
+
+		if len(toolCalls) > 0 {
+			delta.ToolCalls = toolCalls
+		}
+
+		choice := model.ChatCompletionChoice{
 			Index: candidate.Index,
 			Delta: delta,
-		})
+		}
+
+		if candidate.FinishReason != "" {
+			if choice.FinishReason = convFinishReason(candidate.FinishReason); choice.FinishReason == consts.FinishReasonStop && len(toolCalls) > 0 {
+				choice.FinishReason = consts.FinishReasonToolCalls
+			}
+		}
+
+		choice.ContentFilterResults = convSafetyRatings(candidate.SafetyRatings, "")
+		choice.LogProbs = convLogprobsResult(candidate.LogprobsResult)
+
+		response.Choices = append(response.Choices, choice)
+	}
+
+	// 提示词被拦截时没有candidates, 返回content_filter结束流
+	if chatCompletionRes.PromptFeedback != nil && chatCompletionRes.PromptFeedback.BlockReason != "" {
+
+		if contentFilterResults := convSafetyRatings(chatCompletionRes.PromptFeedback.SafetyRatings, chatCompletionRes.PromptFeedback.BlockReason); contentFilterResults != nil {
+			response.PromptAnnotations = []model.PromptAnnotation{{
+				PromptIndex:          0,
+				ContentFilterResults: *contentFilterResults,
+			}}
+		}
+
+		if len(chatCompletionRes.Candidates) == 0 {
+			response.Choices = append(response.Choices, model.ChatCompletionChoice{
+				Delta: &model.ChatCompletionStreamChoiceDelta{
+					Role: consts.ROLE_ASSISTANT,
+				},
+				FinishReason: consts.FinishReasonContentFilter,
+			})
+		}
 	}
 
 	if chatCompletionRes.UsageMetadata != nil {
@@ -207,6 +288,10 @@ func (g *Google) ConvChatCompletionsStreamResponse(ctx context.Context, data []b
 			},
 		}
 
+		// 命中上下文缓存的tokens
+		response.Usage.PromptTokensDetails.CachedTokens = chatCompletionRes.UsageMetadata.CachedContentTokenCount
+		response.Usage.InputTokensDetails.CachedTokens = chatCompletionRes.UsageMetadata.CachedContentTokenCount
+
 		for _, promptTokensDetail := range chatCompletionRes.UsageMetadata.PromptTokensDetails {
 
 			if promptTokensDetail.Modality == "TEXT" {
@@ -305,7 +390,7 @@ func (g *Google) ConvImageGenerationsResponse(ctx context.Context, data []byte)
 		return response, err
 	}
 
-	if chatCompletionRes.Error.Code != 0 || (chatCompletionRes.Candidates[0].FinishReason != "STOP" && chatCompletionRes.Candidates[0].FinishReason != "MAX_TOKENS") {
+	if chatCompletionRes.Error.Code != 0 {
 		logger.Errorf(ctx, "ConvImageGenerationsResponse Google model: %s, chatCompletionRes: %s", g.Model, gjson.MustEncodeString(chatCompletionRes))
 
 		err = g.apiErrorHandler(&chatCompletionRes)
@@ -314,6 +399,11 @@ func (g *Google) ConvImageGenerationsResponse(ctx context.Context, data []byte)
 		return response, err
 	}
 
+	if err = g.contentFilterErrorHandler(&chatCompletionRes); err != nil {
+		logger.Errorf(ctx, "ConvImageGenerationsResponse Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
 	response = model.ImageResponse{
 		Created: gtime.Now().Unix(),
 	}
@@ -345,6 +435,10 @@ func (g *Google) ConvImageGenerationsResponse(ctx context.Context, data []byte)
 			},
 		}
 
+		// 命中上下文缓存的tokens
+		response.Usage.PromptTokensDetails.CachedTokens = chatCompletionRes.UsageMetadata.CachedContentTokenCount
+		response.Usage.InputTokensDetails.CachedTokens = chatCompletionRes.UsageMetadata.CachedContentTokenCount
+
 		for _, promptTokensDetail := range chatCompletionRes.UsageMetadata.PromptTokensDetails {
 
 			if promptTokensDetail.Modality == "TEXT" {
@@ -387,194 +481,1582 @@ func (g *Google) ConvImageEditsResponse(ctx context.Context, data []byte) (respo
 }
 
 func (g *Google) ConvAudioSpeechRequest(ctx context.Context, data []byte) (request model.SpeechRequest, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvAudioSpeechRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	if err = json.Unmarshal(data, &request); err != nil {
+		logger.Error(ctx, err)
+		return request, err
+	}
+
+	return request, nil
 }
 
 func (g *Google) ConvAudioSpeechResponse(ctx context.Context, data []byte) (response model.SpeechResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return g.convAudioSpeechResponse(ctx, data, "")
 }
 
-func (g *Google) ConvAudioTranscriptionsRequest(ctx context.Context, request model.AudioRequest) (data *bytes.Buffer, err error) {
-	//TODO implement me
-	panic("implement me")
-}
+// Gemini返回16位单声道PCM, pcm格式直接返回, 否则封装为WAV
+func (g *Google) convAudioSpeechResponse(ctx context.Context, data []byte, responseFormat string) (response model.SpeechResponse, err error) {
 
-func (g *Google) ConvAudioTranscriptionsResponse(ctx context.Context, data []byte) (response model.AudioResponse, err error) {
-	//TODO implement me
-	panic("implement me")
-}
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvAudioSpeechResponse time: %d", gtime.TimestampMilli()-now)
+	}()
 
-func (g *Google) ConvTextEmbeddingsRequest(ctx context.Context, data []byte) (request model.EmbeddingRequest, err error) {
-	//TODO implement me
-	panic("implement me")
-}
+	chatCompletionRes := model.GoogleChatCompletionRes{}
+	if err = json.Unmarshal(data, &chatCompletionRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
 
-func (g *Google) ConvTextEmbeddingsResponse(ctx context.Context, data []byte) (response model.EmbeddingResponse, err error) {
-	//TODO implement me
-	panic("implement me")
-}
+	if chatCompletionRes.Error.Code != 0 {
+		logger.Errorf(ctx, "ConvAudioSpeechResponse Google model: %s, chatCompletionRes: %s", g.Model, gjson.MustEncodeString(chatCompletionRes))
 
-func (g *Google) ConvVideoCreateRequest(ctx context.Context, request model.VideoCreateRequest) (data *bytes.Buffer, err error) {
-	//TODO implement me
-	panic("implement me")
-}
+		err = g.apiErrorHandler(&chatCompletionRes)
+		logger.Errorf(ctx, "ConvAudioSpeechResponse Google model: %s, error: %v", g.Model, err)
 
-func (g *Google) ConvVideoListResponse(ctx context.Context, data []byte) (response model.VideoListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
-}
+		return response, err
+	}
 
-func (g *Google) ConvVideoContentResponse(ctx context.Context, data []byte) (response model.VideoContentResponse, err error) {
-	//TODO implement me
-	panic("implement me")
-}
+	if err = g.contentFilterErrorHandler(&chatCompletionRes); err != nil {
+		logger.Errorf(ctx, "ConvAudioSpeechResponse Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
 
-func (g *Google) ConvVideoJobResponse(ctx context.Context, data []byte) (response model.VideoJobResponse, err error) {
-	//TODO implement me
-	panic("implement me")
-}
+	var (
+		pcm        = make([]byte, 0)
+		sampleRate = 24000
+	)
 
-func (g *Google) ConvFileUploadRequest(ctx context.Context, request model.FileUploadRequest) (data *bytes.Buffer, err error) {
+	for _, candidate := range chatCompletionRes.Candidates {
+		for _, part := range candidate.Content.Parts {
 
-	now := gtime.TimestampMilli()
-	defer func() {
-		logger.Debugf(ctx, "ConvFileUploadRequest time: %d", gtime.TimestampMilli()-now)
-	}()
+			if part.InlineData == nil || part.InlineData.Data == "" {
+				continue
+			}
 
-	data = &bytes.Buffer{}
-	builder := util.NewFormBuilder(data)
+			// 如: audio/L16;codec=pcm;rate=24000
+			for _, param := range strings.Split(part.InlineData.MimeType, ";") {
+				if rate, found := strings.CutPrefix(strings.TrimSpace(param), "rate="); found {
+					sampleRate = gconv.Int(rate)
+				}
+			}
 
-	defer func() {
-		if err := builder.Close(); err != nil {
-			logger.Errorf(ctx, "ConvFileUploadRequest Google model: %s, builder.Close() error: %v", g.Model, err)
-		}
-	}()
+			audio, err := base64.StdEncoding.DecodeString(part.InlineData.Data)
+			if err != nil {
+				logger.Error(ctx, err)
+				return response, err
+			}
 
-	if request.File != nil {
-		if err = builder.CreateFormFileHeader("file", request.File); err != nil {
-			logger.Errorf(ctx, "ConvFileUploadRequest Google model: %s, error: %v", g.Model, err)
-			return data, err
+			pcm = append(pcm, audio...)
 		}
 	}
 
-	g.header["Content-Type"] = builder.FormDataContentType()
+	if len(pcm) == 0 {
+		return response, errors.New("ConvAudioSpeechResponse Google: response does not contain audio data")
+	}
 
-	return data, nil
+	if responseFormat == "pcm" {
+		response.Data = pcm
+	} else {
+		response.Data = util.PcmToWav(pcm, sampleRate, 1, 16)
+	}
+
+	return response, nil
 }
 
-func (g *Google) ConvFileListResponse(ctx context.Context, data []byte) (response model.FileListResponse, err error) {
+func (g *Google) ConvAudioTranscriptionsRequest(ctx context.Context, request model.AudioRequest) (data *bytes.Buffer, err error) {
 
 	now := gtime.TimestampMilli()
 	defer func() {
-		logger.Debugf(ctx, "ConvFileListResponse time: %d", gtime.TimestampMilli()-now)
+		logger.Debugf(ctx, "ConvAudioTranscriptionsRequest time: %d", gtime.TimestampMilli()-now)
 	}()
 
-	response.ResponseBytes = data
+	if request.File == nil {
+		return nil, errors.New("ConvAudioTranscriptionsRequest Google: file is required")
+	}
 
-	filesRes := model.GoogleFileListResponse{}
-	if err = json.Unmarshal(data, &filesRes); err != nil {
+	file, err := request.File.Open()
+	if err != nil {
 		logger.Error(ctx, err)
-		return response, err
+		return nil, err
 	}
 
-	for _, file := range filesRes.Files {
+	fileBytes, err := io.ReadAll(file)
 
-		fileRes := model.FileResponse{
-			Id:            strings.TrimPrefix(file.Name, "files/"),
-			Object:        "file",
-			Purpose:       "upload",
-			Filename:      strings.TrimPrefix(file.Name, "files/"),
-			Bytes:         gconv.Int(file.SizeBytes),
-			CreatedAt:     file.CreateTime.Unix(),
-			ExpiresAt:     file.ExpirationTime.Unix(),
-			FileUrl:       file.Uri,
-			ResponseBytes: data,
-		}
+	if err := file.Close(); err != nil {
+		logger.Error(ctx, err)
+	}
 
-		if file.State == "PROCESSING" {
-			fileRes.Status = "processing"
-		} else if file.State == "ACTIVE" {
-			fileRes.Status = "processed"
-		} else {
-			fileRes.Status = strings.ToLower(file.State)
-		}
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
 
-		response.Data = append(response.Data, fileRes)
+	prompt := "Generate a transcript of the speech in this audio. Output only the transcribed text without any additional commentary."
+
+	chatCompletionReq := model.GoogleChatCompletionReq{
+		GenerationConfig: model.GenerationConfig{
+			Temperature: request.Temperature,
+		},
 	}
 
-	response.Object = "list"
-	response.HasMore = false
+	// 字幕及详细格式需要时间戳, 通过结构化输出获取分段
+	if isTimedTranscriptionFormat(request.ResponseFormat) {
+
+		prompt = "Generate a transcript of the speech in this audio, split into segments by sentence. For each segment provide the start and end time in seconds and the transcribed text."
+
+		chatCompletionReq.GenerationConfig.ResponseMimeType = "application/json"
+		chatCompletionReq.GenerationConfig.ResponseSchema = map[string]any{
+			"type": "OBJECT",
+			"properties": map[string]any{
+				"language": map[string]any{"type": "STRING"},
+				"segments": map[string]any{
+					"type": "ARRAY",
+					"items": map[string]any{
+						"type": "OBJECT",
+						"properties": map[string]any{
+							"start": map[string]any{"type": "NUMBER"},
+							"end":   map[string]any{"type": "NUMBER"},
+							"text":  map[string]any{"type": "STRING"},
+						},
+						"required": []string{"start", "end", "text"},
+					},
+				},
+			},
+			"required": []string{"segments"},
+		}
+	}
 
-	if len(response.Data) > 0 {
-		response.FirstId = &response.Data[0].Id
-		response.LastId = &response.Data[len(response.Data)-1].Id
+	if request.Language != "" {
+		prompt += fmt.Sprintf(" The language of the audio is %s.", request.Language)
 	}
 
-	return response, nil
+	if request.Prompt != "" {
+		prompt += fmt.Sprintf(" Use the following context to improve the transcription: %s", request.Prompt)
+	}
+
+	chatCompletionReq.Contents = []model.Content{{
+		Role: consts.ROLE_USER,
+		Parts: []model.Part{{
+			Text: prompt,
+		}, {
+			InlineData: &model.InlineData{
+				MimeType: convAudioMimeType(request.File.Filename, request.File.Header.Get("Content-Type")),
+				Data:     base64.StdEncoding.EncodeToString(fileBytes),
+			},
+		}},
+	}}
+
+	return bytes.NewBuffer(gjson.MustEncode(chatCompletionReq)), nil
 }
 
-func (g *Google) ConvFileContentResponse(ctx context.Context, data []byte) (response model.FileContentResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+func (g *Google) ConvAudioTranscriptionsResponse(ctx context.Context, data []byte) (response model.AudioResponse, err error) {
+	return g.convAudioTranscriptionsResponse(ctx, data, "")
 }
 
-func (g *Google) ConvFileResponse(ctx context.Context, data []byte) (response model.FileResponse, err error) {
+// 字幕及详细格式需按请求的response_format解析分段
+func (g *Google) convAudioTranscriptionsResponse(ctx context.Context, data []byte, responseFormat string) (response model.AudioResponse, err error) {
 
 	now := gtime.TimestampMilli()
 	defer func() {
-		logger.Debugf(ctx, "ConvFileResponse time: %d", gtime.TimestampMilli()-now)
+		logger.Debugf(ctx, "ConvAudioTranscriptionsResponse time: %d", gtime.TimestampMilli()-now)
 	}()
 
-	response.ResponseBytes = data
-
-	fileRes := model.GoogleFileResponse{}
-	if err = json.Unmarshal(data, &fileRes); err != nil {
+	chatCompletionRes := model.GoogleChatCompletionRes{}
+	if err = json.Unmarshal(data, &chatCompletionRes); err != nil {
 		logger.Error(ctx, err)
 		return response, err
 	}
 
-	if fileRes.File == (model.GoogleFile{}) {
-		if err = json.Unmarshal(data, &fileRes.File); err != nil {
+	if chatCompletionRes.Error.Code != 0 {
+		logger.Errorf(ctx, "ConvAudioTranscriptionsResponse Google model: %s, chatCompletionRes: %s", g.Model, gjson.MustEncodeString(chatCompletionRes))
+
+		err = g.apiErrorHandler(&chatCompletionRes)
+		logger.Errorf(ctx, "ConvAudioTranscriptionsResponse Google model: %s, error: %v", g.Model, err)
+
+		return response, err
+	}
+
+	if err = g.contentFilterErrorHandler(&chatCompletionRes); err != nil {
+		logger.Errorf(ctx, "ConvAudioTranscriptionsResponse Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	text := ""
+	if len(chatCompletionRes.Candidates) > 0 {
+		for _, part := range chatCompletionRes.Candidates[0].Content.Parts {
+			if !part.Thought {
+				text += part.Text
+			}
+		}
+	}
+
+	response = model.AudioResponse{
+		Task: "transcribe",
+		Text: strings.TrimSpace(text),
+	}
+
+	if isTimedTranscriptionFormat(responseFormat) {
+
+		transcription := struct {
+			Language string `json:"language"`
+			Segments []struct {
+				Start float64 `json:"start"`
+				End   float64 `json:"end"`
+				Text  string  `json:"text"`
+			} `json:"segments"`
+		}{}
+
+		if err = json.Unmarshal([]byte(text), &transcription); err != nil {
 			logger.Error(ctx, err)
 			return response, err
 		}
+
+		texts := make([]string, 0, len(transcription.Segments))
+
+		for i, segment := range transcription.Segments {
+
+			response.Segments = append(response.Segments, model.Segment{
+				Id:    i,
+				Start: segment.Start,
+				End:   segment.End,
+				Text:  strings.TrimSpace(segment.Text),
+			})
+
+			texts = append(texts, strings.TrimSpace(segment.Text))
+
+			response.Duration = math.Max(response.Duration, segment.End)
+		}
+
+		response.Language = transcription.Language
+		response.Text = strings.Join(texts, " ")
 	}
 
-	response = model.FileResponse{
-		Id:            strings.TrimPrefix(fileRes.File.Name, "files/"),
-		Object:        "file",
-		Purpose:       "upload",
-		Bytes:         gconv.Int(fileRes.File.SizeBytes),
-		CreatedAt:     fileRes.File.CreateTime.Unix(),
-		ExpiresAt:     fileRes.File.ExpirationTime.Unix(),
-		FileUrl:       fileRes.File.Uri,
-		ResponseBytes: data,
+	// Gemini音频按每秒32个token计费, 据此换算音频时长
+	if chatCompletionRes.UsageMetadata != nil {
+		for _, detail := range chatCompletionRes.UsageMetadata.PromptTokensDetails {
+			if detail.Modality == "AUDIO" {
+				response.Usage.Type = "duration"
+				response.Usage.Seconds = int(math.Ceil(float64(detail.TokenCount) / 32))
+			}
+		}
 	}
 
-	if fileRes.File.State == "PROCESSING" {
-		response.Status = "processing"
-	} else if fileRes.File.State == "ACTIVE" {
-		response.Status = "processed"
-	} else {
-		response.Status = strings.ToLower(fileRes.File.State)
+	switch responseFormat {
+	case "srt":
+		response.Text = convSubtitles(response.Segments, false)
+	case "vtt":
+		response.Text = convSubtitles(response.Segments, true)
 	}
 
 	return response, nil
 }
 
-func (g *Google) ConvBatchCreateRequest(ctx context.Context, request model.BatchCreateRequest) (data *bytes.Buffer, err error) {
-	//TODO implement me
-	panic("implement me")
+func (g *Google) ConvTextEmbeddingsRequest(ctx context.Context, data []byte) (request model.EmbeddingRequest, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvTextEmbeddingsRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	if err = json.Unmarshal(data, &request); err != nil {
+		logger.Error(ctx, err)
+		return request, err
+	}
+
+	return request, nil
+}
+
+func (g *Google) ConvTextEmbeddingsResponse(ctx context.Context, data []byte) (response model.EmbeddingResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvTextEmbeddingsResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	response = model.EmbeddingResponse{
+		Object:        "list",
+		Data:          make([]any, 0),
+		Model:         g.Model,
+		Usage:         &model.Usage{},
+		ResponseBytes: data,
+	}
+
+	if g.isGcp {
+
+		embeddingRes := model.GoogleVertexEmbeddingRes{}
+		if err = json.Unmarshal(data, &embeddingRes); err != nil {
+			logger.Error(ctx, err)
+			return response, err
+		}
+
+		for i, prediction := range embeddingRes.Predictions {
+			response.Data = append(response.Data, convEmbedding(i, prediction.Embeddings.Values))
+			response.Usage.PromptTokens += prediction.Embeddings.Statistics.TokenCount
+		}
+
+	} else {
+
+		embeddingRes := model.GoogleEmbeddingRes{}
+		if err = json.Unmarshal(data, &embeddingRes); err != nil {
+			logger.Error(ctx, err)
+			return response, err
+		}
+
+		for i, embedding := range embeddingRes.Embeddings {
+			response.Data = append(response.Data, convEmbedding(i, embedding.Values))
+		}
+
+		if embeddingRes.UsageMetadata != nil {
+			response.Usage.PromptTokens = embeddingRes.UsageMetadata.PromptTokenCount
+		}
+	}
+
+	response.Usage.TotalTokens = response.Usage.PromptTokens
+
+	return response, nil
+}
+
+func (g *Google) ConvVideoCreateRequest(ctx context.Context, request model.VideoCreateRequest) (data *bytes.Buffer, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvVideoCreateRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	instance := model.GoogleVideoInstance{
+		Prompt: request.Prompt,
+	}
+
+	// 参考图片作为首帧
+	if request.InputReference != nil {
+
+		file, err := request.InputReference.Open()
+		if err != nil {
+			logger.Errorf(ctx, "ConvVideoCreateRequest Google model: %s, error: %v", g.Model, err)
+			return nil, err
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			logger.Errorf(ctx, "ConvVideoCreateRequest Google model: %s, error: %v", g.Model, err)
+			return nil, err
+		}
+
+		mimeType := request.InputReference.Header.Get("Content-Type")
+		if mimeType == "" || mimeType == "application/octet-stream" {
+			mimeType = http.DetectContentType(content)
+		}
+
+		instance.Image = &model.GoogleVideoMedia{
+			BytesBase64Encoded: base64.StdEncoding.EncodeToString(content),
+			MimeType:           mimeType,
+		}
+	}
+
+	videoReq := model.GoogleVideoReq{
+		Instances:  []model.GoogleVideoInstance{instance},
+		Parameters: &model.GoogleVideoParameters{},
+	}
+
+	if request.Seconds != "" {
+		videoReq.Parameters.DurationSeconds = gconv.Int(request.Seconds)
+	}
+
+	if request.Size != "" {
+		videoReq.Parameters.AspectRatio, videoReq.Parameters.Resolution = convSizeToAspectRatioResolution(request.Size)
+	}
+
+	return bytes.NewBuffer(gjson.MustEncode(videoReq)), nil
+}
+
+func (g *Google) ConvVideoListResponse(ctx context.Context, data []byte) (response model.VideoListResponse, err error) {
+	return response, errors.New("Google does not support listing video operations")
+}
+
+func (g *Google) ConvVideoContentResponse(ctx context.Context, data []byte) (response model.VideoContentResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvVideoContentResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	return model.VideoContentResponse{Data: data}, nil
+}
+
+func (g *Google) ConvVideoJobResponse(ctx context.Context, data []byte) (response model.VideoJobResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvVideoJobResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	operation := model.GoogleVideoOperation{}
+	if err = json.Unmarshal(data, &operation); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	return g.convVideoOperation(operation, data), nil
+}
+
+func (g *Google) ConvCacheCreateRequest(ctx context.Context, request model.CacheCreateRequest) ([]byte, error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvCacheCreateRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	// 复用对话请求的转换, 保证缓存内容与对话时的格式一致
+	data, err := g.ConvChatCompletionsRequestOfficial(ctx, model.ChatCompletionRequest{
+		Model:    request.Model,
+		Messages: request.Messages,
+		Tools:    request.Tools,
+	})
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	chatCompletionReq := model.GoogleChatCompletionReq{}
+	if err = json.Unmarshal(data, &chatCompletionReq); err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	cachedContentModel, err := g.cachedContentModel()
+	if err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	cachedContent := model.GoogleCachedContent{
+		DisplayName:       request.DisplayName,
+		Model:             cachedContentModel,
+		SystemInstruction: chatCompletionReq.SystemInstruction,
+		Contents:          chatCompletionReq.Contents,
+		Tools:             chatCompletionReq.Tools,
+	}
+
+	if request.ExpiresAt > 0 {
+		expireTime := gtime.NewFromTimeStamp(request.ExpiresAt).Time
+		cachedContent.ExpireTime = &expireTime
+	} else if request.Ttl > 0 {
+		cachedContent.Ttl = fmt.Sprintf("%ds", request.Ttl)
+	}
+
+	return gjson.MustEncode(cachedContent), nil
+}
+
+func (g *Google) ConvCacheListResponse(ctx context.Context, data []byte) (response model.CacheListResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvCacheListResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	cachedContentListRes := model.GoogleCachedContentListResponse{}
+	if err = json.Unmarshal(data, &cachedContentListRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	response = model.CacheListResponse{
+		Object:        "list",
+		Data:          make([]model.CacheResponse, 0),
+		HasMore:       cachedContentListRes.NextPageToken != "",
+		ResponseBytes: data,
+	}
+
+	for _, cachedContent := range cachedContentListRes.CachedContents {
+		response.Data = append(response.Data, convCachedContent(cachedContent))
+	}
+
+	if len(response.Data) > 0 {
+		response.FirstId = &response.Data[0].Id
+		response.LastId = &response.Data[len(response.Data)-1].Id
+	}
+
+	// 下一页需使用nextPageToken
+	if response.HasMore {
+		response.LastId = &cachedContentListRes.NextPageToken
+	}
+
+	return response, nil
+}
+
+func (g *Google) ConvCacheResponse(ctx context.Context, data []byte) (response model.CacheResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvCacheResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	cachedContent := model.GoogleCachedContent{}
+	if err = json.Unmarshal(data, &cachedContent); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	response = convCachedContent(cachedContent)
+	response.ResponseBytes = data
+
+	return response, nil
+}
+
+func (g *Google) ConvFileUploadRequest(ctx context.Context, request model.FileUploadRequest) (data *bytes.Buffer, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvFileUploadRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	data = &bytes.Buffer{}
+	builder := util.NewFormBuilder(data)
+
+	defer func() {
+		if err := builder.Close(); err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest Google model: %s, builder.Close() error: %v", g.Model, err)
+		}
+	}()
+
+	if request.File != nil && request.Purpose == "batch" {
+
+		// Gemini无法下载上传的文件, 批处理输入文件需在上传时转换格式
+		file, err := request.File.Open()
+		if err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest Google model: %s, error: %v", g.Model, err)
+			return data, err
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest Google model: %s, error: %v", g.Model, err)
+			return data, err
+		}
+
+		if content, err = g.ConvBatchInputFile(ctx, content); err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest Google model: %s, error: %v", g.Model, err)
+			return data, err
+		}
+
+		if err = builder.CreateFormFileReader("file", bytes.NewReader(content), request.File.Filename); err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest Google model: %s, error: %v", g.Model, err)
+			return data, err
+		}
+
+	} else if request.File != nil {
+		if err = builder.CreateFormFileHeader("file", request.File); err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest Google model: %s, error: %v", g.Model, err)
+			return data, err
+		}
+	}
+
+	g.header["Content-Type"] = builder.FormDataContentType()
+
+	return data, nil
+}
+
+func (g *Google) ConvFileListResponse(ctx context.Context, data []byte) (response model.FileListResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvFileListResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	response.ResponseBytes = data
+
+	filesRes := model.GoogleFileListResponse{}
+	if err = json.Unmarshal(data, &filesRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	for _, file := range filesRes.Files {
+
+		fileRes := model.FileResponse{
+			Id:            strings.TrimPrefix(file.Name, "files/"),
+			Object:        "file",
+			Purpose:       "upload",
+			Filename:      strings.TrimPrefix(file.Name, "files/"),
+			Bytes:         gconv.Int(file.SizeBytes),
+			CreatedAt:     file.CreateTime.Unix(),
+			ExpiresAt:     file.ExpirationTime.Unix(),
+			FileUrl:       file.Uri,
+			ResponseBytes: data,
+		}
+
+		if file.State == "PROCESSING" {
+			fileRes.Status = "processing"
+		} else if file.State == "ACTIVE" {
+			fileRes.Status = "processed"
+		} else {
+			fileRes.Status = strings.ToLower(file.State)
+		}
+
+		response.Data = append(response.Data, fileRes)
+	}
+
+	response.Object = "list"
+	response.HasMore = false
+
+	if len(response.Data) > 0 {
+		response.FirstId = &response.Data[0].Id
+		response.LastId = &response.Data[len(response.Data)-1].Id
+	}
+
+	return response, nil
+}
+
+func (g *Google) ConvFileContentResponse(ctx context.Context, data []byte) (response model.FileContentResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvFileContentResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	response.Data = data
+
+	// 批处理结果文件转换为OpenAI的批处理输出格式, 其它文件原样返回
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	if len(lines) == 0 || !gjson.Valid(lines[0]) || !gjson.New(lines[0]).Contains("key") {
+		return response, nil
+	}
+
+	buf := &bytes.Buffer{}
+
+	for _, line := range lines {
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		batchOutput := model.GoogleBatchOutput{}
+		if err = json.Unmarshal(line, &batchOutput); err != nil {
+			logger.Error(ctx, err)
+			return response, err
+		}
+
+		output := map[string]any{
+			"id":        "batch_req_" + grand.S(24),
+			"custom_id": batchOutput.Key,
+			"response":  nil,
+			"error":     nil,
+		}
+
+		if batchOutput.Error != nil {
+			output["error"] = map[string]any{
+				"code":    gconv.String(batchOutput.Error.Code),
+				"message": batchOutput.Error.Message,
+			}
+		} else {
+
+			chatCompletionRes, err := g.ConvChatCompletionsResponse(ctx, batchOutput.Response)
+			if err != nil {
+				output["error"] = map[string]any{
+					"code":    "api_error",
+					"message": err.Error(),
+				}
+			} else {
+				chatCompletionRes.Object = consts.COMPLETION_OBJECT
+				output["response"] = map[string]any{
+					"status_code": 200,
+					"request_id":  chatCompletionRes.Id,
+					"body":        chatCompletionRes,
+				}
+			}
+		}
+
+		buf.Write(gjson.MustEncode(output))
+		buf.WriteByte('\n')
+	}
+
+	response.Data = buf.Bytes()
+
+	return response, nil
+}
+
+// OpenAI的批处理输入文件转换为Gemini的批处理输入格式, 每行为 {"key": custom_id, "request": GenerateContentRequest}
+func (g *Google) ConvBatchInputFile(ctx context.Context, data []byte) ([]byte, error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchInputFile time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	buf := &bytes.Buffer{}
+
+	for i, line := range bytes.Split(data, []byte("\n")) {
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		batchInput := struct {
+			CustomId string                      `json:"custom_id"`
+			Body     model.ChatCompletionRequest `json:"body"`
+		}{}
+
+		if err := json.Unmarshal(line, &batchInput); err != nil {
+			logger.Errorf(ctx, "ConvBatchInputFile Google model: %s, line: %d, error: %v", g.Model, i+1, err)
+			return nil, err
+		}
+
+		// 每行按各自的模型转换, 未指定时使用当前模型
+		options := *g.AdapterOptions
+		if batchInput.Body.Model != "" {
+			options.Model = batchInput.Body.Model
+		}
+
+		converter := &Google{AdapterOptions: &options, isGcp: g.isGcp}
+
+		request, err := converter.ConvChatCompletionsRequestOfficial(ctx, batchInput.Body)
+		if err != nil {
+			logger.Errorf(ctx, "ConvBatchInputFile Google model: %s, line: %d, error: %v", options.Model, i+1, err)
+			return nil, err
+		}
+
+		chatCompletionReq := model.GoogleChatCompletionReq{}
+		if err = json.Unmarshal(request, &chatCompletionReq); err != nil {
+			logger.Errorf(ctx, "ConvBatchInputFile Google model: %s, line: %d, error: %v", options.Model, i+1, err)
+			return nil, err
+		}
+
+		chatCompletionReq.Model = "models/" + options.Model
+
+		buf.Write(gjson.MustEncode(map[string]any{
+			"key":     batchInput.CustomId,
+			"request": chatCompletionReq,
+		}))
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (g *Google) ConvFileResponse(ctx context.Context, data []byte) (response model.FileResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvFileResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	response.ResponseBytes = data
+
+	fileRes := model.GoogleFileResponse{}
+	if err = json.Unmarshal(data, &fileRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	if fileRes.File == (model.GoogleFile{}) {
+		if err = json.Unmarshal(data, &fileRes.File); err != nil {
+			logger.Error(ctx, err)
+			return response, err
+		}
+	}
+
+	response = model.FileResponse{
+		Id:            strings.TrimPrefix(fileRes.File.Name, "files/"),
+		Object:        "file",
+		Purpose:       "upload",
+		Bytes:         gconv.Int(fileRes.File.SizeBytes),
+		CreatedAt:     fileRes.File.CreateTime.Unix(),
+		ExpiresAt:     fileRes.File.ExpirationTime.Unix(),
+		FileUrl:       fileRes.File.Uri,
+		ResponseBytes: data,
+	}
+
+	if fileRes.File.State == "PROCESSING" {
+		response.Status = "processing"
+	} else if fileRes.File.State == "ACTIVE" {
+		response.Status = "processed"
+	} else {
+		response.Status = strings.ToLower(fileRes.File.State)
+	}
+
+	return response, nil
+}
+
+func (g *Google) ConvBatchCreateRequest(ctx context.Context, request model.BatchCreateRequest) (data *bytes.Buffer, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchCreateRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	batchCreateReq := model.GoogleBatchCreateReq{
+		Batch: model.GoogleBatch{
+			DisplayName: gconv.String(gconv.Map(request.Metadata)["display_name"]),
+		},
+	}
+
+	if batchCreateReq.Batch.DisplayName == "" {
+		batchCreateReq.Batch.DisplayName = "batch-" + request.InputFileId
+	}
+
+	batchCreateReq.Batch.InputConfig = &struct {
+		FileName string `json:"fileName,omitempty"`
+	}{
+		FileName: "files/" + strings.TrimPrefix(request.InputFileId, "files/"),
+	}
+
+	return bytes.NewBuffer(gjson.MustEncode(batchCreateReq)), nil
+}
+
+func (g *Google) ConvBatchListResponse(ctx context.Context, data []byte) (response model.BatchListResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchListResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	batchListRes := model.GoogleBatchListResponse{}
+	if err = json.Unmarshal(data, &batchListRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	response = model.BatchListResponse{
+		Object:  "list",
+		Data:    make([]any, 0),
+		HasMore: batchListRes.NextPageToken != "",
+	}
+
+	for _, operation := range batchListRes.Operations {
+		batchRes := g.convBatchOperation(operation)
+		response.Data = append(response.Data, batchRes)
+		if response.FirstId == nil {
+			response.FirstId = &batchRes.Id
+		}
+		response.LastId = &batchRes.Id
+	}
+
+	// 下一页需使用nextPageToken
+	if response.HasMore {
+		response.LastId = &batchListRes.NextPageToken
+	}
+
+	return response, nil
+}
+
+func (g *Google) ConvBatchResponse(ctx context.Context, data []byte) (response model.BatchResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	operation := model.GoogleBatchOperation{}
+	if err = json.Unmarshal(data, &operation); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	// 获取任务时也可能直接返回GenerateContentBatch
+	if operation.Metadata.Name == "" && operation.Metadata.State == "" {
+		if err = json.Unmarshal(data, &operation.Metadata); err != nil {
+			logger.Error(ctx, err)
+			return response, err
+		}
+	}
+
+	response = g.convBatchOperation(operation)
+	response.ResponseBytes = data
+
+	return response, nil
+}
+
+// 流式工具调用的索引在分片内从0开始, 累加此前分片的工具调用数量, 并在结束时标记为tool_calls
+func convStreamToolCalls(response *model.ChatCompletionResponse, toolCallIndex int) int {
+
+	count := 0
+
+	for _, choice := range response.Choices {
+
+		if choice.Delta == nil {
+			continue
+		}
+
+		toolCalls, ok := choice.Delta.ToolCalls.([]any)
+		if !ok {
+			continue
+		}
+
+		for _, toolCall := range toolCalls {
+			if call, ok := toolCall.(map[string]any); ok {
+				call["index"] = toolCallIndex + gconv.Int(call["index"])
+				count++
+			}
+		}
+	}
+
+	toolCallIndex += count
+
+	for i := range response.Choices {
+		if response.Choices[i].FinishReason == consts.FinishReasonStop && toolCallIndex > 0 {
+			response.Choices[i].FinishReason = consts.FinishReasonToolCalls
+		}
+	}
+
+	return toolCallIndex
+}
+
+// 将Gemini结束原因映射为OpenAI结束原因
+func convFinishReason(finishReason string) string {
+	switch finishReason {
+	case "MAX_TOKENS":
+		return consts.FinishReasonLength
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII", "IMAGE_SAFETY", "IMAGE_PROHIBITED_CONTENT", "IMAGE_RECITATION":
+		return consts.FinishReasonContentFilter
+	default:
+		return consts.FinishReasonStop
+	}
 }
 
-func (g *Google) ConvBatchListResponse(ctx context.Context, data []byte) (response model.BatchListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+// 安全评级转换为content_filter_results, Google的概率等级对应Azure的严重程度
+func convSafetyRatings(safetyRatings []model.SafetyRating, blockReason string) *model.ContentFilterResults {
+
+	if len(safetyRatings) == 0 && blockReason == "" {
+		return nil
+	}
+
+	contentFilterResults := &model.ContentFilterResults{
+		Hate:     model.Hate{Severity: "safe"},
+		SelfHarm: model.SelfHarm{Severity: "safe"},
+		Sexual:   model.Sexual{Severity: "safe"},
+		Violence: model.Violence{Severity: "safe"},
+	}
+
+	for _, safetyRating := range safetyRatings {
+
+		severity := convSeverity(safetyRating.Probability)
+
+		switch safetyRating.Category {
+		case "HARM_CATEGORY_HATE_SPEECH", "HARM_CATEGORY_HARASSMENT":
+			contentFilterResults.Hate.Filtered = contentFilterResults.Hate.Filtered || safetyRating.Blocked
+			contentFilterResults.Hate.Severity = maxSeverity(contentFilterResults.Hate.Severity, severity)
+		case "HARM_CATEGORY_SEXUALLY_EXPLICIT":
+			contentFilterResults.Sexual.Filtered = contentFilterResults.Sexual.Filtered || safetyRating.Blocked
+			contentFilterResults.Sexual.Severity = maxSeverity(contentFilterResults.Sexual.Severity, severity)
+		case "HARM_CATEGORY_DANGEROUS_CONTENT":
+			contentFilterResults.Violence.Filtered = contentFilterResults.Violence.Filtered || safetyRating.Blocked
+			contentFilterResults.Violence.Severity = maxSeverity(contentFilterResults.Violence.Severity, severity)
+		}
+	}
+
+	if blockReason == "BLOCKLIST" {
+		contentFilterResults.Profanity = model.Profanity{Filtered: true, Detected: true}
+	}
+
+	return contentFilterResults
 }
 
-func (g *Google) ConvBatchResponse(ctx context.Context, data []byte) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+func convSeverity(probability string) string {
+	switch probability {
+	case "LOW":
+		return "low"
+	case "MEDIUM":
+		return "medium"
+	case "HIGH":
+		return "high"
+	default:
+		return "safe"
+	}
+}
+
+func maxSeverity(a, b string) string {
+
+	levels := map[string]int{"safe": 0, "low": 1, "medium": 2, "high": 3}
+
+	if levels[b] > levels[a] {
+		return b
+	}
+
+	return a
+}
+
+func convLogprobsResult(logprobsResult *model.LogprobsResult) *model.LogProbs {
+
+	if logprobsResult == nil || len(logprobsResult.ChosenCandidates) == 0 {
+		return nil
+	}
+
+	logProbs := &model.LogProbs{
+		Content: make([]model.LogProb, 0, len(logprobsResult.ChosenCandidates)),
+	}
+
+	for i, chosenCandidate := range logprobsResult.ChosenCandidates {
+
+		logProb := model.LogProb{
+			Token:       chosenCandidate.Token,
+			LogProb:     chosenCandidate.LogProbability,
+			TopLogProbs: make([]model.TopLogProbs, 0),
+		}
+
+		if i < len(logprobsResult.TopCandidates) {
+			for _, topCandidate := range logprobsResult.TopCandidates[i].Candidates {
+				logProb.TopLogProbs = append(logProb.TopLogProbs, model.TopLogProbs{
+					Token:   topCandidate.Token,
+					LogProb: topCandidate.LogProbability,
+				})
+			}
+		}
+
+		logProbs.Content = append(logProbs.Content, logProb)
+	}
+
+	return logProbs
+}
+
+func convCachedContent(cachedContent model.GoogleCachedContent) model.CacheResponse {
+
+	cacheRes := model.CacheResponse{
+		Id:          convCacheId(cachedContent.Name),
+		Object:      "cache",
+		Model:       cachedContent.Model[strings.LastIndex(cachedContent.Model, "/")+1:],
+		DisplayName: cachedContent.DisplayName,
+	}
+
+	if cachedContent.CreateTime != nil {
+		cacheRes.CreatedAt = cachedContent.CreateTime.Unix()
+	}
+
+	if cachedContent.UpdateTime != nil {
+		cacheRes.UpdatedAt = cachedContent.UpdateTime.Unix()
+	}
+
+	if cachedContent.ExpireTime != nil {
+		cacheRes.ExpiresAt = cachedContent.ExpireTime.Unix()
+	}
+
+	if cachedContent.UsageMetadata != nil {
+		cacheRes.Usage = &model.Usage{
+			PromptTokens: cachedContent.UsageMetadata.TotalTokenCount,
+			TotalTokens:  cachedContent.UsageMetadata.TotalTokenCount,
+		}
+	}
+
+	return cacheRes
+}
+
+// 缓存名称格式: cachedContents/{id} 或 projects/{project}/locations/{location}/cachedContents/{id}
+func convCacheId(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+func convEmbedding(index int, values []float64) map[string]any {
+	return map[string]any{
+		"object":    "embedding",
+		"index":     index,
+		"embedding": values,
+	}
+}
+
+// encoding_format为base64时, 按OpenAI的格式返回小端float32数组的base64编码
+func convEmbeddingsBase64(data []any) {
+
+	for _, value := range data {
+
+		embedding, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		values, ok := embedding["embedding"].([]float64)
+		if !ok {
+			continue
+		}
+
+		buf := make([]byte, len(values)*4)
+		for i, value := range values {
+			binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(float32(value)))
+		}
+
+		embedding["embedding"] = base64.StdEncoding.EncodeToString(buf)
+	}
+}
+
+// Gemini没有公开分词器, 使用o200k_base估算
+func numEmbeddingTokens(inputs []string) (numTokens int) {
+
+	for _, input := range inputs {
+		if tokens, err := tiktoken.NumTokensFromString("gpt-4o", input); err == nil {
+			numTokens += tokens
+		}
+	}
+
+	return numTokens
+}
+
+func (g *Google) convBatchOperation(operation model.GoogleBatchOperation) model.BatchResponse {
+
+	batch := operation.Metadata
+	if batch.Name == "" {
+		batch.Name = operation.Name
+	}
+
+	batchRes := model.BatchResponse{
+		Id:               strings.TrimPrefix(batch.Name, "batches/"),
+		Object:           "batch",
+		Endpoint:         "/v1/chat/completions",
+		Model:            strings.TrimPrefix(batch.Model, "models/"),
+		CompletionWindow: "24h",
+		Status:           convBatchStatus(batch.State),
+		Metadata: map[string]any{
+			"display_name": batch.DisplayName,
+		},
+	}
+
+	if batch.InputConfig != nil {
+		batchRes.InputFileId = strings.TrimPrefix(batch.InputConfig.FileName, "files/")
+	}
+
+	if batch.Output != nil && batch.Output.ResponsesFile != "" {
+		batchRes.OutputFileId = strings.TrimPrefix(batch.Output.ResponsesFile, "files/")
+	} else if operation.Response != nil && operation.Response.ResponsesFile != "" {
+		batchRes.OutputFileId = strings.TrimPrefix(operation.Response.ResponsesFile, "files/")
+	}
+
+	if batch.BatchStats != nil {
+		batchRes.RequestCounts = model.RequestCounts{
+			Total:     gconv.Int(batch.BatchStats.RequestCount),
+			Completed: gconv.Int(batch.BatchStats.SuccessfulRequestCount),
+			Failed:    gconv.Int(batch.BatchStats.FailedRequestCount),
+		}
+	}
+
+	if batch.CreateTime != nil {
+		batchRes.CreatedAt = batch.CreateTime.Unix()
+	}
+
+	if batch.State == "BATCH_STATE_RUNNING" || batch.State == "JOB_STATE_RUNNING" {
+		if batch.UpdateTime != nil {
+			batchRes.InProgressAt = batch.UpdateTime.Unix()
+		}
+	}
+
+	if batch.EndTime != nil {
+		switch batchRes.Status {
+		case "completed":
+			batchRes.CompletedAt = batch.EndTime.Unix()
+		case "failed":
+			batchRes.FailedAt = batch.EndTime.Unix()
+		case "cancelled":
+			batchRes.CancelledAt = batch.EndTime.Unix()
+		case "expired":
+			batchRes.ExpiredAt = batch.EndTime.Unix()
+		}
+	}
+
+	if operation.Error != nil {
+		batchRes.Errors = &model.BatchError{
+			Object: "list",
+			Data: []struct {
+				Code    string `json:"code"`
+				Line    int    `json:"line"`
+				Message string `json:"message"`
+				Param   string `json:"param"`
+			}{{
+				Code:    gconv.String(operation.Error.Code),
+				Message: operation.Error.Message,
+			}},
+		}
+	}
+
+	return batchRes
+}
+
+// 批处理状态, Gemini API为BATCH_STATE_*, SDK中为JOB_STATE_*
+func convBatchStatus(state string) string {
+
+	state = strings.TrimPrefix(strings.TrimPrefix(state, "BATCH_STATE_"), "JOB_STATE_")
+
+	switch state {
+	case "PENDING", "QUEUED":
+		return "validating"
+	case "RUNNING":
+		return "in_progress"
+	case "SUCCEEDED":
+		return "completed"
+	case "FAILED":
+		return "failed"
+	case "CANCELLING":
+		return "cancelling"
+	case "CANCELLED":
+		return "cancelled"
+	case "EXPIRED":
+		return "expired"
+	default:
+		return strings.ToLower(state)
+	}
+}
+
+func (g *Google) convVideoOperation(operation model.GoogleVideoOperation, data []byte) model.VideoJobResponse {
+
+	response := model.VideoJobResponse{
+		Id:            convVideoId(operation.Name),
+		Object:        "video",
+		Model:         convVideoModel(operation.Name),
+		Status:        "in_progress",
+		Progress:      gconv.Int(operation.Metadata["progressPercent"]),
+		ResponseBytes: data,
+	}
+
+	if response.Model == "" {
+		response.Model = g.Model
+	}
+
+	if !operation.Done {
+		return response
+	}
+
+	response.Progress = 100
+
+	if operation.Error != nil {
+		response.Status = "failed"
+		response.Error = &model.VideoError{
+			Code:    gconv.String(operation.Error.Code),
+			Message: operation.Error.Message,
+		}
+		return response
+	}
+
+	response.Status = "completed"
+
+	if operation.Response == nil {
+		return response
+	}
+
+	var (
+		raiMediaFilteredCount   = operation.Response.RaiMediaFilteredCount
+		raiMediaFilteredReasons = operation.Response.RaiMediaFilteredReasons
+	)
+
+	if operation.Response.GenerateVideoResponse != nil {
+
+		for _, generatedSample := range operation.Response.GenerateVideoResponse.GeneratedSamples {
+			if response.VideoUrl == "" {
+				response.VideoUrl = generatedSample.Video.Uri
+			}
+		}
+
+		raiMediaFilteredCount = operation.Response.GenerateVideoResponse.RaiMediaFilteredCount
+		raiMediaFilteredReasons = operation.Response.GenerateVideoResponse.RaiMediaFilteredReasons
+	}
+
+	// Vertex未指定storageUri时直接返回视频数据, 需通过VideoContent获取
+	for _, video := range operation.Response.Videos {
+		if response.VideoUrl == "" && video.GcsUri != "" {
+			response.VideoUrl = video.GcsUri
+		}
+	}
+
+	if response.VideoUrl == "" && len(operation.Response.Videos) == 0 && raiMediaFilteredCount > 0 {
+		response.Status = "failed"
+		response.Error = &model.VideoError{
+			Code:    consts.FinishReasonContentFilter,
+			Message: strings.Join(raiMediaFilteredReasons, "; "),
+		}
+	}
+
+	return response
+}
+
+// 操作名称格式: models/{model}/operations/{id} 或 projects/{project}/locations/{location}/publishers/google/models/{model}/operations/{id}
+func convVideoId(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+func convVideoModel(name string) string {
+
+	if i := strings.Index(name, "models/"); i != -1 {
+		modelName := name[i+len("models/"):]
+		if j := strings.Index(modelName, "/"); j != -1 {
+			return modelName[:j]
+		}
+		return modelName
+	}
+
+	return ""
+}
+
+// 将 "1280x720" 格式转换为 aspectRatio("16:9") 和 resolution("720p"), Veo仅支持横屏和竖屏
+func convSizeToAspectRatioResolution(size string) (aspectRatio, resolution string) {
+
+	var width, height int
+	for _, sep := range []string{"x", "X", "×", "*"} {
+		if parts := strings.Split(size, sep); len(parts) == 2 {
+			width = gconv.Int(parts[0])
+			height = gconv.Int(parts[1])
+			break
+		}
+	}
+
+	if width <= 0 || height <= 0 {
+		return "", ""
+	}
+
+	if width >= height {
+		aspectRatio = "16:9"
+	} else {
+		aspectRatio = "9:16"
+	}
+
+	if min(width, height) >= 1080 {
+		resolution = "1080p"
+	} else {
+		resolution = "720p"
+	}
+
+	return aspectRatio, resolution
+}
+
+// OpenAI音色与Gemini预置音色的映射, 未匹配时原样传递, 以支持直接使用Gemini音色
+var voiceMapping = map[string]string{
+	"alloy":   "Zephyr",
+	"ash":     "Orus",
+	"ballad":  "Sadaltager",
+	"coral":   "Aoede",
+	"echo":    "Charon",
+	"fable":   "Fenrir",
+	"nova":    "Kore",
+	"onyx":    "Algenib",
+	"sage":    "Leda",
+	"shimmer": "Despina",
+	"verse":   "Puck",
+}
+
+func convVoice(voice string) string {
+
+	if voice == "" {
+		return "Kore"
+	}
+
+	if voiceName, ok := voiceMapping[strings.ToLower(voice)]; ok {
+		return voiceName
+	}
+
+	return voice
+}
+
+// Gemini对无参数的函数可能不返回args, 按OpenAI的格式返回空对象
+func convFunctionArguments(args any) string {
+
+	if args == nil {
+		return "{}"
+	}
+
+	if arguments := gconv.String(args); arguments != "" && arguments != "null" {
+		return arguments
+	}
+
+	return "{}"
+}
+
+func isTimedTranscriptionFormat(responseFormat string) bool {
+	return responseFormat == "verbose_json" || responseFormat == "srt" || responseFormat == "vtt"
+}
+
+// 上传文件未携带有效类型时, 根据扩展名推断音频类型
+func convAudioMimeType(filename, contentType string) string {
+
+	if contentType != "" && contentType != "application/octet-stream" {
+		return contentType
+	}
+
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".mp3", ".mpga", ".mpeg":
+		return "audio/mp3"
+	case ".wav":
+		return "audio/wav"
+	case ".aiff", ".aif":
+		return "audio/aiff"
+	case ".aac", ".m4a", ".mp4":
+		return "audio/aac"
+	case ".ogg", ".oga", ".opus":
+		return "audio/ogg"
+	case ".flac":
+		return "audio/flac"
+	case ".webm":
+		return "audio/webm"
+	}
+
+	return "audio/mp3"
+}
+
+func convSubtitles(segments []model.Segment, isVtt bool) string {
+
+	builder := strings.Builder{}
+
+	separator := ","
+	if isVtt {
+		separator = "."
+		builder.WriteString("WEBVTT\n\n")
+	}
+
+	for i, segment := range segments {
+
+		if !isVtt {
+			builder.WriteString(fmt.Sprintf("%d\n", i+1))
+		}
+
+		builder.WriteString(fmt.Sprintf("%s --> %s\n%s\n\n", convSubtitleTime(segment.Start, separator), convSubtitleTime(segment.End, separator), segment.Text))
+	}
+
+	return builder.String()
+}
+
+func convSubtitleTime(seconds float64, separator string) string {
+
+	millis := int64(math.Round(seconds * 1000))
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", millis/3600000, millis/60000%60, millis/1000%60, separator, millis%1000)
+}
+
+func convContentText(contentParts []any) (text string) {
+
+	for _, contentPart := range contentParts {
+		if part, ok := contentPart.(map[string]any); ok && part["type"] == "text" {
+			text += gconv.String(part["text"])
+		}
+	}
+
+	return text
+}
+
+// 转换candidate的parts, 包含图片等非文本内容时, content为类型化的内容数组, 否则为字符串
+func (g *Google) convContentParts(parts []model.Part, isStream bool) (content any, reasoningContent string, toolCalls []any) {
+
+	var (
+		text         string
+		contentParts = make([]any, 0)
+		hasMedia     bool
+	)
+
+	appendText := func(value string) {
+
+		text += value
+
+		// 相邻的文本合并为同一个内容块
+		if len(contentParts) > 0 {
+			if last, ok := contentParts[len(contentParts)-1].(map[string]any); ok && last["type"] == "text" {
+				last["text"] = gconv.String(last["text"]) + value
+				return
+			}
+		}
+
+		contentParts = append(contentParts, map[string]any{
+			"type": "text",
+			"text": value,
+		})
+	}
+
+	for _, part := range parts {
+
+		if part.Thought {
+			reasoningContent += part.Text
+			continue
+		}
+
+		if part.Text != "" {
+			appendText(part.Text)
+		}
+
+		if part.ExecutableCode != nil {
+			appendText(fmt.Sprintf("\n```%s\n%s\n```\n", strings.ToLower(part.ExecutableCode.Language), part.ExecutableCode.Code))
+		}
+
+		if part.CodeExecutionResult != nil && part.CodeExecutionResult.Output != "" {
+			appendText(fmt.Sprintf("\n```\n%s\n```\n", part.CodeExecutionResult.Output))
+		}
+
+		if part.InlineData != nil && part.InlineData.Data != "" {
+
+			hasMedia = true
+			dataUri := fmt.Sprintf("data:%s;base64,%s", part.InlineData.MimeType, part.InlineData.Data)
+
+			if strings.HasPrefix(part.InlineData.MimeType, "image/") {
+				contentParts = append(contentParts, map[string]any{
+					"type": "image_url",
+					"image_url": map[string]any{
+						"url": dataUri,
+					},
+				})
+			} else {
+				contentParts = append(contentParts, map[string]any{
+					"type": "file",
+					"file": map[string]any{
+						"file_data": dataUri,
+					},
+				})
+			}
+		}
+
+		if part.FileData != nil && part.FileData.FileUri != "" {
+
+			hasMedia = true
+
+			if strings.HasPrefix(part.FileData.MimeType, "image/") {
+				contentParts = append(contentParts, map[string]any{
+					"type": "image_url",
+					"image_url": map[string]any{
+						"url": part.FileData.FileUri,
+					},
+				})
+			} else {
+				contentParts = append(contentParts, map[string]any{
+					"type": "file",
+					"file": map[string]any{
+						"file_id": part.FileData.FileUri,
+					},
+				})
+			}
+		}
+
+		if part.FunctionCall != nil {
+			if functionCall, ok := part.FunctionCall.(map[string]any); ok {
+
+				// Gemini返回的functionCall可能带有id, 后续functionResponse需使用相同的id
+				id := gconv.String(functionCall["id"])
+				if id == "" {
+					id = "call_" + grand.S(24)
+				}
+
+				toolCall := map[string]any{
+					"id":   id,
+					"type": "function",
+					"function": map[string]any{
+						"name":      functionCall["name"],
+						"arguments": convFunctionArguments(functionCall["args"]),
+					},
+					"extra_content": map[string]any{
+						"google": map[string]any{
+							"thought_signature": part.ThoughtSignature,
+						},
+					},
+				}
+
+				if isStream {
+					// 分片内的索引, 整个流中的索引由流式处理累加偏移
+					toolCall["index"] = len(toolCalls)
+				}
+
+				toolCalls = append(toolCalls, toolCall)
+			}
+		}
+	}
+
+	if hasMedia {
+		return contentParts, reasoningContent, toolCalls
+	}
+
+	return text, reasoningContent, toolCalls
 }
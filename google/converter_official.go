@@ -3,11 +3,15 @@ package google
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"io"
 	"mime/multipart"
+	"sort"
+	"sync"
 
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
 	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/iimeta/fastapi-sdk/v2/common"
 	"github.com/iimeta/fastapi-sdk/v2/consts"
@@ -22,7 +26,12 @@ func (g *Google) ConvChatCompletionsRequestOfficial(ctx context.Context, request
 		logger.Debugf(ctx, "ConvChatCompletionsRequestOfficial time: %d", gtime.TimestampMilli()-now)
 	}()
 
-	contents := make([]model.Content, 0)
+	var (
+		contents          = make([]model.Content, 0)
+		toolCallNames     = make(map[string]string)
+		systemInstruction *model.Content
+	)
+
 	for _, message := range request.Messages {
 
 		role := message.Role
@@ -31,6 +40,60 @@ func (g *Google) ConvChatCompletionsRequestOfficial(ctx context.Context, request
 			role = consts.ROLE_MODEL
 		}
 
+		if role == consts.ROLE_SYSTEM || role == consts.ROLE_DEVELOPER {
+
+			// 不支持系统角色的模型(如Gemma)按用户消息处理
+			if g.IsSupportSystemRole != nil && !*g.IsSupportSystemRole {
+				role = consts.ROLE_USER
+			} else {
+
+				if systemInstruction == nil {
+					systemInstruction = &model.Content{
+						Parts: make([]model.Part, 0),
+					}
+				}
+
+				systemInstruction.Parts = append(systemInstruction.Parts, convContentPartsOfficial(message.Content)...)
+
+				continue
+			}
+		}
+
+		// 工具结果转为functionResponse, 名称通过tool_call_id匹配之前的functionCall
+		if role == consts.ROLE_TOOL || role == consts.ROLE_FUNCTION {
+
+			name := message.Name
+			if toolCallName, ok := toolCallNames[message.ToolCallId]; ok {
+				name = toolCallName
+			}
+
+			functionResponse := map[string]any{
+				"name":     name,
+				"response": convFunctionResponse(message.Content),
+			}
+
+			// 携带id与对应的functionCall匹配
+			if message.ToolCallId != "" {
+				functionResponse["id"] = message.ToolCallId
+			}
+
+			part := model.Part{
+				FunctionResponse: functionResponse,
+			}
+
+			// 连续的工具结果需合并到同一轮中, 与上一轮的functionCall一一对应
+			if len(contents) > 0 && isFunctionResponseContent(contents[len(contents)-1]) {
+				contents[len(contents)-1].Parts = append(contents[len(contents)-1].Parts, part)
+			} else {
+				contents = append(contents, model.Content{
+					Role:  consts.ROLE_USER,
+					Parts: []model.Part{part},
+				})
+			}
+
+			continue
+		}
+
 		parts := make([]model.Part, 0)
 
 		if contents, ok := message.Content.([]any); ok {
@@ -74,12 +137,54 @@ func (g *Google) ConvChatCompletionsRequestOfficial(ctx context.Context, request
 				}
 			}
 
-		} else {
+		} else if text := gconv.String(message.Content); text != "" {
+			parts = append(parts, model.Part{
+				Text: text,
+			})
+		}
+
+		if message.FunctionCall != nil {
 			parts = append(parts, model.Part{
-				Text: gconv.String(message.Content),
+				FunctionCall: map[string]any{
+					"name": message.FunctionCall.Name,
+					"args": convFunctionArgs(message.FunctionCall.Arguments),
+				},
 			})
 		}
 
+		for i, toolCall := range convToolCalls(message.ToolCalls) {
+
+			function, _ := toolCall["function"].(map[string]any)
+			name := gconv.String(function["name"])
+
+			functionCall := map[string]any{
+				"name": name,
+				"args": convFunctionArgs(function["arguments"]),
+			}
+
+			if id := gconv.String(toolCall["id"]); id != "" {
+				toolCallNames[id] = name
+				functionCall["id"] = id
+			}
+
+			thoughtSignature := getThoughtSignature(toolCall)
+
+			// Gemini 3 要求每轮首个functionCall携带签名, 来自其它模型的历史消息使用官方提供的占位签名跳过校验
+			if thoughtSignature == nil && i == 0 && gstr.Contains(g.Model, "gemini-3") {
+				thoughtSignature = "skip_thought_signature_validator"
+			}
+
+			parts = append(parts, model.Part{
+				FunctionCall:     functionCall,
+				ThoughtSignature: thoughtSignature,
+			})
+		}
+
+		// 空消息(无内容且无工具调用)会导致Gemini返回400, 直接跳过
+		if len(parts) == 0 {
+			continue
+		}
+
 		contents = append(contents, model.Content{
 			Role:  role,
 			Parts: parts,
@@ -89,10 +194,53 @@ func (g *Google) ConvChatCompletionsRequestOfficial(ctx context.Context, request
 	chatCompletionReq := model.GoogleChatCompletionReq{
 		Contents: contents,
 		GenerationConfig: model.GenerationConfig{
-			MaxOutputTokens: request.MaxTokens,
-			Temperature:     request.Temperature,
-			TopP:            request.TopP,
+			StopSequences:    request.Stop,
+			CandidateCount:   request.N,
+			MaxOutputTokens:  request.MaxTokens,
+			Temperature:      request.Temperature,
+			TopP:             request.TopP,
+			TopK:             request.TopK,
+			PresencePenalty:  request.PresencePenalty,
+			FrequencyPenalty: request.FrequencyPenalty,
+			Seed:             request.Seed,
+			ResponseLogprobs: request.LogProbs,
 		},
+		SafetySettings:    request.SafetySettings,
+		SystemInstruction: systemInstruction,
+	}
+
+	chatCompletionReq.GenerationConfig.ThinkingConfig = convThinkingConfig(g.Model, request)
+
+	// 引用上下文缓存时, systemInstruction和tools已包含在缓存中, 不能重复设置
+	if request.CacheId != "" {
+		cachedContentName, err := g.cachedContentName(request.CacheId)
+		if err != nil {
+			logger.Error(ctx, err)
+			return nil, err
+		}
+		chatCompletionReq.CachedContent = cachedContentName
+		chatCompletionReq.SystemInstruction = nil
+		request.Tools = nil
+	}
+
+	if chatCompletionReq.GenerationConfig.MaxOutputTokens == 0 {
+		chatCompletionReq.GenerationConfig.MaxOutputTokens = request.MaxCompletionTokens
+	}
+
+	if request.LogProbs {
+		chatCompletionReq.GenerationConfig.Logprobs = request.TopLogProbs
+	}
+
+	if request.ResponseFormat != nil {
+		switch request.ResponseFormat.Type {
+		case "json_object":
+			chatCompletionReq.GenerationConfig.ResponseMimeType = "application/json"
+		case "json_schema":
+			chatCompletionReq.GenerationConfig.ResponseMimeType = "application/json"
+			if jsonSchema, ok := gconv.Map(request.ResponseFormat.JSONSchema)["schema"].(map[string]any); ok {
+				chatCompletionReq.GenerationConfig.ResponseSchema = convResponseSchema(jsonSchema, jsonSchema, 0)
+			}
+		}
 	}
 
 	if request.Tools != nil {
@@ -119,13 +267,248 @@ func (g *Google) ConvChatCompletionsRequestOfficial(ctx context.Context, request
 }
 
 func (g *Google) ConvChatCompletionsResponseOfficial(ctx context.Context, response model.ChatCompletionResponse) ([]byte, error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvChatCompletionsResponseOfficial time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	chatCompletionRes := model.GoogleChatCompletionRes{
+		Candidates:    make([]model.Candidate, 0),
+		UsageMetadata: convUsageMetadataOfficial(response.Usage),
+		ModelVersion:  response.Model,
+		ResponseId:    response.Id,
+	}
+
+	for _, choice := range response.Choices {
+
+		candidate := model.Candidate{
+			Content: model.Content{
+				Role:  consts.ROLE_MODEL,
+				Parts: make([]model.Part, 0),
+			},
+			FinishReason: convFinishReasonOfficial(choice.FinishReason),
+			Index:        choice.Index,
+		}
+
+		if choice.Message != nil {
+
+			if choice.Message.ReasoningContent != nil && gconv.String(choice.Message.ReasoningContent) != "" {
+				candidate.Content.Parts = append(candidate.Content.Parts, model.Part{
+					Text:    gconv.String(choice.Message.ReasoningContent),
+					Thought: true,
+				})
+			}
+
+			candidate.Content.Parts = append(candidate.Content.Parts, convContentPartsOfficial(choice.Message.Content)...)
+
+			for _, toolCall := range convToolCalls(choice.Message.ToolCalls) {
+				if function, ok := toolCall["function"].(map[string]any); ok {
+					candidate.Content.Parts = append(candidate.Content.Parts, model.Part{
+						FunctionCall: map[string]any{
+							"id":   toolCall["id"],
+							"name": function["name"],
+							"args": convFunctionArgs(function["arguments"]),
+						},
+						ThoughtSignature: getThoughtSignature(toolCall),
+					})
+				}
+			}
+		}
+
+		chatCompletionRes.Candidates = append(chatCompletionRes.Candidates, candidate)
+	}
+
+	return gjson.MustEncode(chatCompletionRes), nil
 }
 
+// ConvChatCompletionsStreamResponseOfficial 不跨分片缓存工具调用, 每个分片中的工具调用直接输出, 参数被拆分到多个分片时需使用NewStreamConverter
 func (g *Google) ConvChatCompletionsStreamResponseOfficial(ctx context.Context, response model.ChatCompletionResponse) ([]byte, error) {
-	//TODO implement me
-	panic("implement me")
+	return g.convChatCompletionsStreamResponseOfficial(ctx, response, make(map[int]*streamToolCall), true)
+}
+
+// StreamConverter 按流缓存未完成的工具调用, 调用方需为每个流创建一个实例
+type StreamConverter struct {
+	google    *Google
+	toolCalls map[int]*streamToolCall
+	mutex     sync.Mutex
+}
+
+// NewStreamConverter 流式响应转换为Gemini格式时, 工具调用参数可能被拆分到多个分片, 需按流缓存后输出
+func (g *Google) NewStreamConverter() *StreamConverter {
+	return &StreamConverter{
+		google:    g,
+		toolCalls: make(map[int]*streamToolCall),
+	}
+}
+
+func (s *StreamConverter) ConvChatCompletionsStreamResponseOfficial(ctx context.Context, response model.ChatCompletionResponse) ([]byte, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.google.convChatCompletionsStreamResponseOfficial(ctx, response, s.toolCalls, false)
+}
+
+// isFlush为true时每个分片结束即输出全部工具调用, 否则在收到结束原因时输出未完成的工具调用
+func (g *Google) convChatCompletionsStreamResponseOfficial(ctx context.Context, response model.ChatCompletionResponse, streamToolCalls map[int]*streamToolCall, isFlush bool) ([]byte, error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvChatCompletionsStreamResponseOfficial time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	chatCompletionRes := model.GoogleChatCompletionRes{
+		Candidates:    make([]model.Candidate, 0),
+		UsageMetadata: convUsageMetadataOfficial(response.Usage),
+		ModelVersion:  response.Model,
+		ResponseId:    response.Id,
+	}
+
+	for _, choice := range response.Choices {
+
+		candidate := model.Candidate{
+			Content: model.Content{
+				Role:  consts.ROLE_MODEL,
+				Parts: make([]model.Part, 0),
+			},
+			FinishReason: convFinishReasonOfficial(choice.FinishReason),
+			Index:        choice.Index,
+		}
+
+		if choice.Delta != nil {
+
+			if choice.Delta.ReasoningContent != nil && gconv.String(choice.Delta.ReasoningContent) != "" {
+				candidate.Content.Parts = append(candidate.Content.Parts, model.Part{
+					Text:    gconv.String(choice.Delta.ReasoningContent),
+					Thought: true,
+				})
+			}
+
+			if len(choice.Delta.ContentParts) > 0 {
+				candidate.Content.Parts = append(candidate.Content.Parts, convContentPartsOfficial(choice.Delta.ContentParts)...)
+			} else {
+				candidate.Content.Parts = append(candidate.Content.Parts, convContentPartsOfficial(choice.Delta.Content)...)
+			}
+
+			for i, toolCall := range convToolCalls(choice.Delta.ToolCalls) {
+
+				index := i
+				if toolCall["index"] != nil {
+					index = gconv.Int(toolCall["index"])
+				}
+
+				call, ok := streamToolCalls[index]
+				if !ok {
+					call = new(streamToolCall)
+					streamToolCalls[index] = call
+				}
+
+				if id := gconv.String(toolCall["id"]); id != "" {
+					call.id = id
+				}
+
+				if thoughtSignature := getThoughtSignature(toolCall); thoughtSignature != nil {
+					call.thoughtSignature = thoughtSignature
+				}
+
+				if function, ok := toolCall["function"].(map[string]any); ok {
+
+					if name := gconv.String(function["name"]); name != "" {
+						call.name = name
+					}
+
+					if arguments, ok := function["arguments"].(string); ok {
+						call.arguments += arguments
+					} else if function["arguments"] != nil {
+						call.arguments = gjson.MustEncodeString(function["arguments"])
+					}
+				}
+
+				// 参数可能被拆分到多个分片, 拼接为完整JSON后才输出functionCall
+				if call.arguments == "" || !json.Valid([]byte(call.arguments)) {
+					continue
+				}
+
+				candidate.Content.Parts = append(candidate.Content.Parts, convStreamToolCallOfficial(call))
+
+				delete(streamToolCalls, index)
+			}
+		}
+
+		// 结束时输出全部未完成的工具调用, 无参数的函数参数为空
+		if isFlush || choice.FinishReason != "" {
+
+			indexes := make([]int, 0, len(streamToolCalls))
+			for index := range streamToolCalls {
+				indexes = append(indexes, index)
+			}
+
+			sort.Ints(indexes)
+
+			for _, index := range indexes {
+				candidate.Content.Parts = append(candidate.Content.Parts, convStreamToolCallOfficial(streamToolCalls[index]))
+				delete(streamToolCalls, index)
+			}
+		}
+
+		if len(candidate.Content.Parts) == 0 && candidate.FinishReason == "" {
+			continue
+		}
+
+		chatCompletionRes.Candidates = append(chatCompletionRes.Candidates, candidate)
+	}
+
+	return gjson.MustEncode(chatCompletionRes), nil
+}
+
+func (g *Google) ConvTextEmbeddingsRequestOfficial(ctx context.Context, request model.EmbeddingRequest) ([]byte, error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvTextEmbeddingsRequestOfficial time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	inputs := convEmbeddingInputs(request.Input)
+
+	if g.isGcp {
+
+		embeddingReq := model.GoogleVertexEmbeddingReq{
+			Instances: make([]model.GoogleVertexEmbeddingInstance, 0, len(inputs)),
+			Parameters: &model.GoogleVertexEmbeddingParameters{
+				OutputDimensionality: request.Dimensions,
+				AutoTruncate:         true,
+			},
+		}
+
+		for _, input := range inputs {
+			embeddingReq.Instances = append(embeddingReq.Instances, model.GoogleVertexEmbeddingInstance{
+				Content:  input,
+				TaskType: request.TaskType,
+				Title:    request.Title,
+			})
+		}
+
+		return gjson.MustEncode(embeddingReq), nil
+	}
+
+	embeddingReq := model.GoogleEmbeddingReq{
+		Requests: make([]model.GoogleEmbedContentReq, 0, len(inputs)),
+	}
+
+	for _, input := range inputs {
+		embeddingReq.Requests = append(embeddingReq.Requests, model.GoogleEmbedContentReq{
+			Model: "models/" + g.Model,
+			Content: model.Content{
+				Parts: []model.Part{{Text: input}},
+			},
+			TaskType:             request.TaskType,
+			Title:                request.Title,
+			OutputDimensionality: request.Dimensions,
+		})
+	}
+
+	return gjson.MustEncode(embeddingReq), nil
 }
 
 func (g *Google) ConvImageGenerationsRequestOfficial(ctx context.Context, request model.ImageGenerationRequest) ([]byte, error) {
@@ -232,3 +615,408 @@ func (g *Google) ConvImageEditsResponseOfficial(ctx context.Context, response mo
 	//TODO implement me
 	panic("implement me")
 }
+
+func (g *Google) ConvAudioSpeechRequestOfficial(ctx context.Context, request model.SpeechRequest) ([]byte, error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvAudioSpeechRequestOfficial time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	text := request.Input
+
+	// Gemini TTS通过自然语言控制语速
+	if request.Speed > 0 && request.Speed < 1 {
+		text = "Say slowly: " + text
+	} else if request.Speed > 1 {
+		text = "Say quickly: " + text
+	}
+
+	chatCompletionReq := model.GoogleChatCompletionReq{
+		Contents: []model.Content{{
+			Role: consts.ROLE_USER,
+			Parts: []model.Part{{
+				Text: text,
+			}},
+		}},
+		GenerationConfig: model.GenerationConfig{
+			ResponseModalities: []string{"AUDIO"},
+			SpeechConfig: &model.SpeechConfig{
+				VoiceConfig: &model.VoiceConfig{
+					PrebuiltVoiceConfig: &model.PrebuiltVoiceConfig{
+						VoiceName: convVoice(request.Voice),
+					},
+				},
+			},
+		},
+	}
+
+	return gjson.MustEncode(chatCompletionReq), nil
+}
+
+func convEmbeddingInputs(input any) []string {
+
+	if input, ok := input.(string); ok {
+		return []string{input}
+	}
+
+	return gconv.Strings(input)
+}
+
+// 流式工具调用的参数可能被拆分到多个分片中
+type streamToolCall struct {
+	id               string
+	name             string
+	arguments        string
+	thoughtSignature any
+}
+
+// 参数为空或不完整时以空对象输出
+func convStreamToolCallOfficial(call *streamToolCall) model.Part {
+	return model.Part{
+		FunctionCall: map[string]any{
+			"id":   call.id,
+			"name": call.name,
+			"args": convFunctionArgs(call.arguments),
+		},
+		ThoughtSignature: call.thoughtSignature,
+	}
+}
+
+// 将OpenAI结束原因映射为Gemini结束原因
+func convFinishReasonOfficial(finishReason string) string {
+	switch finishReason {
+	case "":
+		return ""
+	case consts.FinishReasonLength:
+		return "MAX_TOKENS"
+	case consts.FinishReasonContentFilter:
+		return "SAFETY"
+	default:
+		return "STOP"
+	}
+}
+
+func convUsageMetadataOfficial(usage *model.Usage) *model.UsageMetadata {
+
+	if usage == nil {
+		return nil
+	}
+
+	usageMetadata := &model.UsageMetadata{
+		PromptTokenCount:        usage.PromptTokens,
+		CandidatesTokenCount:    usage.CompletionTokens,
+		TotalTokenCount:         usage.TotalTokens,
+		ThoughtsTokenCount:      usage.OutputTokensDetails.ReasoningTokens,
+		CachedContentTokenCount: usage.PromptTokensDetails.CachedTokens,
+	}
+
+	// OpenAI的completion_tokens包含推理tokens, Gemini的candidatesTokenCount不包含
+	if usage.CompletionTokensDetails.ReasoningTokens > 0 {
+		usageMetadata.ThoughtsTokenCount = usage.CompletionTokensDetails.ReasoningTokens
+		usageMetadata.CandidatesTokenCount = max(usage.CompletionTokens-usage.CompletionTokensDetails.ReasoningTokens, 0)
+	}
+
+	if usageMetadata.TotalTokenCount == 0 {
+		usageMetadata.TotalTokenCount = usageMetadata.PromptTokenCount + usageMetadata.CandidatesTokenCount + usageMetadata.ThoughtsTokenCount
+	}
+
+	if usage.PromptTokensDetails.TextTokens > 0 {
+		usageMetadata.PromptTokensDetails = append(usageMetadata.PromptTokensDetails, model.ModalityTokenCount{Modality: "TEXT", TokenCount: usage.PromptTokensDetails.TextTokens})
+	}
+
+	if usage.PromptTokensDetails.ImageTokens > 0 {
+		usageMetadata.PromptTokensDetails = append(usageMetadata.PromptTokensDetails, model.ModalityTokenCount{Modality: "IMAGE", TokenCount: usage.PromptTokensDetails.ImageTokens})
+	}
+
+	if usage.CompletionTokensDetails.TextTokens > 0 {
+		usageMetadata.CandidatesTokensDetails = append(usageMetadata.CandidatesTokensDetails, model.ModalityTokenCount{Modality: "TEXT", TokenCount: usage.CompletionTokensDetails.TextTokens})
+	}
+
+	if usage.CompletionTokensDetails.ImageTokens > 0 {
+		usageMetadata.CandidatesTokensDetails = append(usageMetadata.CandidatesTokensDetails, model.ModalityTokenCount{Modality: "IMAGE", TokenCount: usage.CompletionTokensDetails.ImageTokens})
+	}
+
+	return usageMetadata
+}
+
+// 将OpenAI消息内容转换为Gemini parts
+func convContentPartsOfficial(content any) []model.Part {
+
+	parts := make([]model.Part, 0)
+
+	if contents, ok := content.([]any); ok {
+
+		for _, value := range contents {
+
+			if content, ok := value.(map[string]any); ok {
+
+				if content["type"] == "image_url" {
+
+					if imageUrl, ok := content["image_url"].(map[string]any); ok {
+
+						mimeType, data := common.GetMime(gconv.String(imageUrl["url"]))
+
+						parts = append(parts, model.Part{
+							InlineData: &model.InlineData{
+								MimeType: mimeType,
+								Data:     data,
+							},
+						})
+					}
+
+				} else if text := gconv.String(content["text"]); text != "" {
+					parts = append(parts, model.Part{
+						Text: text,
+					})
+				}
+			}
+		}
+
+	} else if text := gconv.String(content); text != "" {
+		parts = append(parts, model.Part{
+			Text: text,
+		})
+	}
+
+	return parts
+}
+
+// 统一转换tool_calls, 兼容[]any和[]model.ToolCall等类型
+func convToolCalls(toolCalls any) []map[string]any {
+
+	if toolCalls == nil {
+		return nil
+	}
+
+	list := make([]map[string]any, 0)
+	if err := json.Unmarshal(gjson.MustEncode(toolCalls), &list); err != nil {
+		return nil
+	}
+
+	return list
+}
+
+func convFunctionArgs(arguments any) map[string]any {
+
+	args := make(map[string]any)
+
+	if v, ok := arguments.(string); ok {
+		if v != "" {
+			_ = json.Unmarshal([]byte(v), &args)
+		}
+	} else if arguments != nil {
+		args = gconv.Map(arguments)
+	}
+
+	return args
+}
+
+func getThoughtSignature(toolCall map[string]any) any {
+
+	if extraContent, ok := toolCall["extra_content"].(map[string]any); ok {
+		if google, ok := extraContent["google"].(map[string]any); ok {
+			return google["thought_signature"]
+		}
+	}
+
+	return nil
+}
+
+// 工具结果需为JSON对象, 非对象内容统一包装为{"content": ...}
+func convFunctionResponse(content any) map[string]any {
+
+	text := ""
+
+	if contents, ok := content.([]any); ok {
+		for _, value := range contents {
+			if part, ok := value.(map[string]any); ok && part["type"] == "text" {
+				text += gconv.String(part["text"])
+			}
+		}
+	} else {
+		text = gconv.String(content)
+	}
+
+	response := make(map[string]any)
+	if err := json.Unmarshal([]byte(text), &response); err != nil || response == nil {
+		return map[string]any{"content": text}
+	}
+
+	return response
+}
+
+func isFunctionResponseContent(content model.Content) bool {
+
+	if content.Role != consts.ROLE_USER || len(content.Parts) == 0 {
+		return false
+	}
+
+	for _, part := range content.Parts {
+		if part.FunctionResponse == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// JSON Schema转换为Gemini支持的OpenAPI Schema子集, 展开$ref并去除不支持的字段
+func convResponseSchema(schema, root map[string]any, depth int) map[string]any {
+
+	// 防止循环引用
+	if depth > 16 {
+		return map[string]any{"type": "OBJECT"}
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+
+		var definition any
+		if gstr.HasPrefix(ref, "#/$defs/") {
+			definition = gconv.Map(root["$defs"])[gstr.TrimLeftStr(ref, "#/$defs/")]
+		} else if gstr.HasPrefix(ref, "#/definitions/") {
+			definition = gconv.Map(root["definitions"])[gstr.TrimLeftStr(ref, "#/definitions/")]
+		}
+
+		if definition, ok := definition.(map[string]any); ok {
+			return convResponseSchema(definition, root, depth+1)
+		}
+
+		return map[string]any{"type": "OBJECT"}
+	}
+
+	result := make(map[string]any)
+
+	for key, value := range schema {
+		switch key {
+		case "type":
+			if types, ok := value.([]any); ok {
+				// ["string", "null"] 转为 STRING + nullable
+				for _, typ := range types {
+					if typ == "null" {
+						result["nullable"] = true
+					} else if result["type"] == nil {
+						result["type"] = gstr.ToUpper(gconv.String(typ))
+					}
+				}
+			} else if value == "null" {
+				result["nullable"] = true
+			} else {
+				result["type"] = gstr.ToUpper(gconv.String(value))
+			}
+		case "properties":
+			properties := make(map[string]any)
+			for name, property := range gconv.Map(value) {
+				if property, ok := property.(map[string]any); ok {
+					properties[name] = convResponseSchema(property, root, depth+1)
+				}
+			}
+			result[key] = properties
+		case "items":
+			if items, ok := value.(map[string]any); ok {
+				result[key] = convResponseSchema(items, root, depth+1)
+			}
+		case "anyOf", "oneOf":
+			anyOf := make([]any, 0)
+			for _, item := range gconv.SliceAny(value) {
+				if item, ok := item.(map[string]any); ok {
+					if item["type"] == "null" {
+						result["nullable"] = true
+						continue
+					}
+					anyOf = append(anyOf, convResponseSchema(item, root, depth+1))
+				}
+			}
+			if len(anyOf) == 1 {
+				for k, v := range anyOf[0].(map[string]any) {
+					result[k] = v
+				}
+			} else if len(anyOf) > 1 {
+				result["anyOf"] = anyOf
+			}
+		case "enum":
+			// Gemini的enum仅支持字符串
+			result[key] = gconv.Strings(value)
+		case "const":
+			result["enum"] = []string{gconv.String(value)}
+		case "format", "title", "description", "nullable", "required", "minItems", "maxItems", "minProperties", "maxProperties",
+			"minLength", "maxLength", "pattern", "minimum", "maximum", "default", "example", "propertyOrdering":
+			result[key] = value
+		}
+	}
+
+	if result["enum"] != nil && result["type"] == nil {
+		result["type"] = "STRING"
+	}
+
+	return result
+}
+
+// reasoning_effort/enable_thinking转换为thinkingConfig, Gemini 3使用thinkingLevel, 之前的模型使用thinkingBudget
+func convThinkingConfig(modelName string, request model.ChatCompletionRequest) *model.ThinkingConfig {
+
+	if request.ReasoningEffort == "" && request.EnableThinking == nil {
+		return nil
+	}
+
+	var (
+		isGemini3      = gstr.Contains(modelName, "gemini-3")
+		isFlash        = gstr.Contains(modelName, "flash")
+		thinkingConfig = &model.ThinkingConfig{
+			IncludeThoughts: true,
+		}
+	)
+
+	effort := request.ReasoningEffort
+	if effort == "" {
+		if *request.EnableThinking {
+			effort = "auto"
+		} else {
+			effort = "none"
+		}
+	}
+
+	if isGemini3 {
+		switch effort {
+		case "none", "minimal":
+			// Gemini 3 无法完全关闭思考, Pro仅支持low/high
+			thinkingConfig.IncludeThoughts = effort != "none"
+			if isFlash {
+				thinkingConfig.ThinkingLevel = "minimal"
+			} else {
+				thinkingConfig.ThinkingLevel = "low"
+			}
+		case "low", "high":
+			thinkingConfig.ThinkingLevel = effort
+		case "medium":
+			if isFlash {
+				thinkingConfig.ThinkingLevel = "medium"
+			} else {
+				thinkingConfig.ThinkingLevel = "high"
+			}
+		}
+		return thinkingConfig
+	}
+
+	thinkingBudget := -1 // 动态思考
+
+	switch effort {
+	case "none":
+		thinkingBudget = 0
+		thinkingConfig.IncludeThoughts = false
+		// 2.5 Pro 无法关闭思考, 使用最小预算
+		if gstr.Contains(modelName, "pro") {
+			thinkingBudget = 128
+		}
+	case "minimal", "low":
+		thinkingBudget = 1024
+	case "medium":
+		thinkingBudget = 8192
+	case "high":
+		thinkingBudget = 24576
+	}
+
+	thinkingConfig.ThinkingBudget = &thinkingBudget
+
+	return thinkingConfig
+}
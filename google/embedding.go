@@ -2,11 +2,71 @@ package google
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (g *Google) TextEmbeddings(ctx context.Context, data []byte) (response model.EmbeddingResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "TextEmbeddings Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "TextEmbeddings Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	request, err := g.ConvTextEmbeddingsRequest(ctx, data)
+	if err != nil {
+		logger.Errorf(ctx, "TextEmbeddings Google ConvTextEmbeddingsRequest error: %v", err)
+		return response, err
+	}
+
+	if data, err = g.ConvTextEmbeddingsRequestOfficial(ctx, request); err != nil {
+		logger.Errorf(ctx, "TextEmbeddings Google ConvTextEmbeddingsRequestOfficial error: %v", err)
+		return response, err
+	}
+
+	if g.Path == "" {
+		g.Path = "/models/" + g.Model
+	}
+
+	if g.Action == "" {
+		if g.isGcp {
+			g.Action = "predict"
+		} else {
+			g.Action = "batchEmbedContents"
+		}
+	}
+
+	bytes, responseHeader, err := util.HttpPost(ctx, fmt.Sprintf("%s%s:%s", g.BaseUrl, g.Path, g.Action), g.header, data, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "TextEmbeddings Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvTextEmbeddingsResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "TextEmbeddings Google ConvTextEmbeddingsResponse error: %v", err)
+		return response, err
+	}
+
+	if request.EncodingFormat == "base64" {
+		convEmbeddingsBase64(response.Data)
+	}
+
+	// batchEmbedContents不返回用量, 按输入文本估算
+	if response.Usage != nil && response.Usage.PromptTokens == 0 {
+		response.Usage.PromptTokens = numEmbeddingTokens(convEmbeddingInputs(request.Input))
+		response.Usage.TotalTokens = response.Usage.PromptTokens
+	}
+
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "TextEmbeddings Google model: %s finished", g.Model)
+
+	return response, nil
 }
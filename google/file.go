@@ -147,6 +147,36 @@ func (g *Google) FileDelete(ctx context.Context, request model.FileDeleteRequest
 }
 
 func (g *Google) FileContent(ctx context.Context, request model.FileContentRequest) (response model.FileContentResponse, err error) {
-	//TODO implement me
-	return response, err
+
+	logger.Infof(ctx, "FileContent Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "FileContent Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	// 仅支持下载批处理等生成的文件
+	if g.Path == "" {
+		g.Path = fmt.Sprintf("/download/v1beta/files/%s:download?alt=media", strings.TrimPrefix(request.FileId, "files/"))
+	}
+
+	if strings.HasSuffix(g.BaseUrl, "/v1beta") && strings.HasPrefix(g.Path, "/download/v1beta") {
+		g.BaseUrl = strings.TrimSuffix(g.BaseUrl, "/v1beta")
+	}
+
+	bytes, _, err := util.HttpGet(ctx, g.BaseUrl+g.Path, g.header, nil, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "FileContent Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvFileContentResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "FileContent Google ConvFileContentResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "FileContent Google model: %s finished", g.Model)
+
+	return response, nil
 }
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 
 	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/iimeta/fastapi-sdk/v2/consts"
 	"github.com/iimeta/fastapi-sdk/v2/errors"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
@@ -15,8 +17,15 @@ import (
 
 type Google struct {
 	*options.AdapterOptions
-	header map[string]string
-	isGcp  bool
+	header             map[string]string
+	isGcp              bool
+	realtimeSetup      bool
+	realtimeResponseId string
+	realtimeItemId     string
+	realtimeOutput     []model.RealtimeConversationItem
+	realtimeCallNames  map[string]string
+	realtimeUsage      *model.Usage
+	realtimeMutex      sync.Mutex
 }
 
 func NewAdapter(ctx context.Context, options *options.AdapterOptions) *Google {
@@ -79,3 +88,21 @@ func (g *Google) requestErrorHandler(ctx context.Context, response *http.Respons
 func (g *Google) apiErrorHandler(response *model.GoogleChatCompletionRes) error {
 	return errors.NewApiError(response.Error.Code, response.Error.Code, gjson.MustEncodeString(response), "api_error", "")
 }
+
+// 提示词被拦截或生成内容因安全等原因终止时返回content_filter错误
+func (g *Google) contentFilterErrorHandler(response *model.GoogleChatCompletionRes) error {
+
+	if response.PromptFeedback != nil && response.PromptFeedback.BlockReason != "" {
+		return errors.NewApiError(400, consts.FinishReasonContentFilter, fmt.Sprintf("The prompt was blocked, reason: %s. %s", response.PromptFeedback.BlockReason, response.PromptFeedback.BlockReasonMessage), "invalid_request_error", "prompt")
+	}
+
+	if len(response.Candidates) == 0 {
+		return errors.NewApiError(500, "api_error", gjson.MustEncodeString(response), "api_error", "")
+	}
+
+	if finishReason := response.Candidates[0].FinishReason; finishReason != "" && finishReason != "STOP" && finishReason != "MAX_TOKENS" {
+		return errors.NewApiError(400, consts.FinishReasonContentFilter, fmt.Sprintf("The response was blocked, finish reason: %s", finishReason), "invalid_request_error", "prompt")
+	}
+
+	return nil
+}
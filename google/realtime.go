@@ -0,0 +1,472 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/gogf/gf/v2/util/grand"
+	"github.com/iimeta/fastapi-sdk/v2/consts"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
+	"github.com/iimeta/fastapi-sdk/v2/model"
+)
+
+// Gemini Live的输入输出音频均为16位PCM, OpenAI Realtime的pcm16为24kHz
+const realtimeAudioMimeType = "audio/pcm;rate=24000"
+
+// ConvRealtimeSessionCreated Gemini Live没有session.created事件, 连接建立后模拟返回
+func (g *Google) ConvRealtimeSessionCreated(ctx context.Context) []byte {
+	return newRealtimeEvent(consts.REALTIME_EVENT_SESSION_CREATED, model.RealtimeServerEvent{
+		Session: &model.RealtimeSession{
+			Id:         "sess_" + grand.S(24),
+			Object:     "realtime.session",
+			Model:      g.Model,
+			Modalities: []string{"audio", "text"},
+		},
+	})
+}
+
+// ConvRealtimeReset 重连后需重新发送setup, 并丢弃未完成的响应状态
+func (g *Google) ConvRealtimeReset(ctx context.Context) {
+
+	g.realtimeMutex.Lock()
+	defer g.realtimeMutex.Unlock()
+
+	g.realtimeSetup = false
+	g.realtimeResponseId = ""
+	g.realtimeOutput = nil
+	g.realtimeUsage = nil
+}
+
+// ConvRealtimeRequest 将OpenAI Realtime客户端事件转换为Gemini Live消息, 首条消息前需发送setup
+func (g *Google) ConvRealtimeRequest(ctx context.Context, message []byte) (messages [][]byte, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvRealtimeRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	// 读写分别在不同协程中进行, 共享会话状态
+	g.realtimeMutex.Lock()
+	defer g.realtimeMutex.Unlock()
+
+	event := model.RealtimeClientEvent{}
+	if err = json.Unmarshal(message, &event); err != nil {
+		logger.Error(ctx, err)
+		return nil, err
+	}
+
+	if !g.realtimeSetup {
+
+		g.realtimeSetup = true
+
+		var session *model.RealtimeSession
+		if event.Type == consts.REALTIME_EVENT_SESSION_UPDATE {
+			session = event.Session
+		}
+
+		messages = append(messages, gjson.MustEncode(model.GoogleLiveClientMessage{
+			Setup: g.convRealtimeSetup(session),
+		}))
+
+		if event.Type == consts.REALTIME_EVENT_SESSION_UPDATE {
+			return messages, nil
+		}
+	}
+
+	clientMessage := model.GoogleLiveClientMessage{}
+
+	switch event.Type {
+	case consts.REALTIME_EVENT_INPUT_AUDIO_BUFFER_APPEND:
+		clientMessage.RealtimeInput = &model.GoogleLiveRealtimeInput{
+			Audio: &model.InlineData{
+				MimeType: realtimeAudioMimeType,
+				Data:     event.Audio,
+			},
+		}
+	case consts.REALTIME_EVENT_INPUT_AUDIO_BUFFER_COMMIT:
+		clientMessage.RealtimeInput = &model.GoogleLiveRealtimeInput{
+			AudioStreamEnd: true,
+		}
+	case consts.REALTIME_EVENT_CONVERSATION_ITEM_CREATE:
+
+		item := event.Item
+		if item == nil {
+			item = new(model.RealtimeConversationItem)
+		}
+
+		if item.Type == "function_call_output" {
+
+			var response any = map[string]any{"output": item.Output}
+			if json.Valid([]byte(item.Output)) {
+				if result := make(map[string]any); json.Unmarshal([]byte(item.Output), &result) == nil {
+					response = result
+				}
+			}
+
+			clientMessage.ToolResponse = &model.GoogleLiveToolResponse{
+				FunctionResponses: []model.GoogleLiveFunctionResponse{{
+					Id:       item.CallId,
+					Name:     g.realtimeCallNames[item.CallId],
+					Response: response,
+				}},
+			}
+
+		} else {
+
+			role := consts.ROLE_USER
+			if item.Role == consts.ROLE_ASSISTANT {
+				role = consts.ROLE_MODEL
+			}
+
+			parts := make([]model.Part, 0)
+
+			for _, content := range item.Content {
+				switch content.Type {
+				case "input_text", "text", "output_text":
+					parts = append(parts, model.Part{Text: content.Text})
+				case "input_audio":
+					parts = append(parts, model.Part{InlineData: &model.InlineData{MimeType: realtimeAudioMimeType, Data: content.Audio}})
+				}
+			}
+
+			// 需等待response.create才开始生成
+			clientMessage.ClientContent = &model.GoogleLiveClientContent{
+				Turns: []model.Content{{
+					Role:  role,
+					Parts: parts,
+				}},
+			}
+		}
+
+	case consts.REALTIME_EVENT_RESPONSE_CREATE:
+		clientMessage.ClientContent = &model.GoogleLiveClientContent{
+			TurnComplete: true,
+		}
+	default:
+		// Gemini Live不支持会话中修改配置及取消响应等事件, 忽略
+		logger.Debugf(ctx, "ConvRealtimeRequest Google model: %s, ignore event type: %s", g.Model, event.Type)
+		return messages, nil
+	}
+
+	return append(messages, gjson.MustEncode(clientMessage)), nil
+}
+
+// ConvRealtimeResponse 将Gemini Live服务端消息转换为OpenAI Realtime服务端事件
+func (g *Google) ConvRealtimeResponse(ctx context.Context, message []byte) (events [][]byte, usage *model.Usage, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvRealtimeResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	g.realtimeMutex.Lock()
+	defer g.realtimeMutex.Unlock()
+
+	serverMessage := model.GoogleLiveServerMessage{}
+	if err = json.Unmarshal(message, &serverMessage); err != nil {
+		logger.Error(ctx, err)
+		return nil, nil, err
+	}
+
+	if serverMessage.Error != nil {
+		events = append(events, newRealtimeEvent(consts.REALTIME_EVENT_ERROR, model.RealtimeServerEvent{
+			Error: &model.RealtimeError{
+				Type:    "server_error",
+				Code:    serverMessage.Error.Status,
+				Message: serverMessage.Error.Message,
+			},
+		}))
+		return events, nil, nil
+	}
+
+	if serverMessage.SetupComplete != nil {
+		events = append(events, newRealtimeEvent(consts.REALTIME_EVENT_SESSION_UPDATED, model.RealtimeServerEvent{
+			Session: &model.RealtimeSession{
+				Object: "realtime.session",
+				Model:  g.Model,
+			},
+		}))
+	}
+
+	if serverContent := serverMessage.ServerContent; serverContent != nil {
+
+		if serverContent.Interrupted {
+			events = append(events, newRealtimeEvent(consts.REALTIME_EVENT_INPUT_AUDIO_BUFFER_SPEECH_STARTED, model.RealtimeServerEvent{}))
+		}
+
+		if serverContent.InputTranscription != nil && serverContent.InputTranscription.Text != "" {
+			events = append(events, newRealtimeEvent(consts.REALTIME_EVENT_INPUT_AUDIO_TRANSCRIPTION_DELTA, model.RealtimeServerEvent{
+				ItemId: g.realtimeItemId,
+				Delta:  serverContent.InputTranscription.Text,
+			}))
+		}
+
+		if serverContent.ModelTurn != nil {
+			for _, part := range serverContent.ModelTurn.Parts {
+
+				if part.Thought {
+					continue
+				}
+
+				if part.InlineData != nil && part.InlineData.Data != "" {
+					events = append(events, g.realtimeResponseStarted()...)
+					events = append(events, newRealtimeEvent(consts.REALTIME_EVENT_RESPONSE_AUDIO_DELTA, model.RealtimeServerEvent{
+						ResponseId: g.realtimeResponseId,
+						ItemId:     g.realtimeItemId,
+						Delta:      part.InlineData.Data,
+					}))
+				}
+
+				if part.Text != "" {
+					events = append(events, g.realtimeResponseStarted()...)
+					events = append(events, newRealtimeEvent(consts.REALTIME_EVENT_RESPONSE_TEXT_DELTA, model.RealtimeServerEvent{
+						ResponseId: g.realtimeResponseId,
+						ItemId:     g.realtimeItemId,
+						Delta:      part.Text,
+					}))
+				}
+			}
+		}
+
+		if serverContent.OutputTranscription != nil && serverContent.OutputTranscription.Text != "" {
+			events = append(events, g.realtimeResponseStarted()...)
+			events = append(events, newRealtimeEvent(consts.REALTIME_EVENT_RESPONSE_AUDIO_TRANSCRIPT_DELTA, model.RealtimeServerEvent{
+				ResponseId: g.realtimeResponseId,
+				ItemId:     g.realtimeItemId,
+				Delta:      serverContent.OutputTranscription.Text,
+			}))
+		}
+	}
+
+	if serverMessage.ToolCall != nil {
+
+		events = append(events, g.realtimeResponseStarted()...)
+
+		for _, functionCall := range serverMessage.ToolCall.FunctionCalls {
+
+			if g.realtimeCallNames == nil {
+				g.realtimeCallNames = make(map[string]string)
+			}
+
+			g.realtimeCallNames[functionCall.Id] = functionCall.Name
+
+			arguments := convFunctionArguments(functionCall.Args)
+
+			item := model.RealtimeConversationItem{
+				Id:        "item_" + grand.S(24),
+				Object:    "realtime.item",
+				Type:      "function_call",
+				Status:    "completed",
+				CallId:    functionCall.Id,
+				Name:      functionCall.Name,
+				Arguments: arguments,
+			}
+
+			events = append(events, newRealtimeEvent(consts.REALTIME_EVENT_RESPONSE_FUNCTION_CALL_ARGUMENTS_DONE, model.RealtimeServerEvent{
+				ResponseId:  g.realtimeResponseId,
+				ItemId:      item.Id,
+				OutputIndex: len(g.realtimeOutput),
+				CallId:      item.CallId,
+				Name:        item.Name,
+				Arguments:   item.Arguments,
+			}))
+
+			g.realtimeOutput = append(g.realtimeOutput, item)
+		}
+	}
+
+	// 用量可能与内容分开下发, 暂存至本轮响应结束时返回
+	if serverMessage.UsageMetadata != nil {
+		g.realtimeUsage = convLiveUsageMetadata(serverMessage.UsageMetadata)
+	}
+
+	// 工具调用后模型等待工具结果, 与OpenAI一致视为本轮响应结束
+	if (serverMessage.ServerContent != nil && serverMessage.ServerContent.TurnComplete) || serverMessage.ToolCall != nil {
+		usage = g.realtimeUsage
+		g.realtimeUsage = nil
+		events = append(events, g.realtimeResponseDone(usage))
+	}
+
+	return events, usage, nil
+}
+
+func (g *Google) convRealtimeSetup(session *model.RealtimeSession) *model.GoogleLiveSetup {
+
+	setup := &model.GoogleLiveSetup{
+		Model: "models/" + g.Model,
+		GenerationConfig: &model.GenerationConfig{
+			ResponseModalities: []string{"AUDIO"},
+		},
+		InputAudioTranscription:  &struct{}{},
+		OutputAudioTranscription: &struct{}{},
+	}
+
+	if strings.HasPrefix(g.Model, "models/") || strings.Contains(g.Model, "/publishers/") {
+		setup.Model = g.Model
+	}
+
+	if session == nil {
+		return setup
+	}
+
+	if session.Instructions != "" {
+		setup.SystemInstruction = &model.Content{
+			Parts: []model.Part{{Text: session.Instructions}},
+		}
+	}
+
+	// Gemini Live每个会话只支持一种输出模态
+	if len(session.Modalities) > 0 && !slices.Contains(session.Modalities, "audio") {
+		setup.GenerationConfig.ResponseModalities = []string{"TEXT"}
+		setup.OutputAudioTranscription = nil
+	}
+
+	if session.Voice != "" && setup.GenerationConfig.ResponseModalities[0] == "AUDIO" {
+		setup.GenerationConfig.SpeechConfig = &model.SpeechConfig{
+			VoiceConfig: &model.VoiceConfig{
+				PrebuiltVoiceConfig: &model.PrebuiltVoiceConfig{
+					VoiceName: convVoice(session.Voice),
+				},
+			},
+		}
+	}
+
+	if session.Temperature != nil {
+		setup.GenerationConfig.Temperature = gconv.Float32(*session.Temperature)
+	}
+
+	// max_response_output_tokens可能为inf
+	if maxTokens := gconv.Int(session.MaxResponseOutputTokens); maxTokens > 0 {
+		setup.GenerationConfig.MaxOutputTokens = maxTokens
+	}
+
+	if len(session.Tools) > 0 {
+
+		var functionDeclarations []any
+
+		for _, tool := range session.Tools {
+			functionDeclarations = append(functionDeclarations, map[string]any{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"parameters":  tool.Parameters,
+			})
+		}
+
+		setup.Tools = []any{map[string]any{
+			"functionDeclarations": functionDeclarations,
+		}}
+	}
+
+	return setup
+}
+
+// 一轮响应的首个内容到达时发送response.created
+func (g *Google) realtimeResponseStarted() [][]byte {
+
+	if g.realtimeResponseId != "" {
+		return nil
+	}
+
+	g.realtimeResponseId = "resp_" + grand.S(24)
+	g.realtimeItemId = "item_" + grand.S(24)
+
+	return [][]byte{newRealtimeEvent(consts.REALTIME_EVENT_RESPONSE_CREATED, model.RealtimeServerEvent{
+		Response: &model.RealtimeResponseObject{
+			Id:     g.realtimeResponseId,
+			Object: "realtime.response",
+			Status: "in_progress",
+			Output: []model.RealtimeConversationItem{},
+		},
+	})}
+}
+
+func (g *Google) realtimeResponseDone(usage *model.Usage) []byte {
+
+	response := &model.RealtimeResponseObject{
+		Id:     g.realtimeResponseId,
+		Object: "realtime.response",
+		Status: "completed",
+		Output: g.realtimeOutput,
+	}
+
+	if response.Output == nil {
+		response.Output = []model.RealtimeConversationItem{}
+	}
+
+	if usage != nil {
+
+		response.Usage = &model.RealtimeUsage{
+			TotalTokens:  usage.TotalTokens,
+			InputTokens:  usage.PromptTokens,
+			OutputTokens: usage.CompletionTokens,
+		}
+
+		response.Usage.InputTokenDetails.CachedTokens = usage.InputTokensDetails.CachedTokens
+		response.Usage.InputTokenDetails.TextTokens = usage.InputTokensDetails.TextTokens
+		response.Usage.InputTokenDetails.AudioTokens = usage.InputTokensDetails.AudioTokens
+		response.Usage.OutputTokenDetails.TextTokens = usage.CompletionTokensDetails.TextTokens
+		response.Usage.OutputTokenDetails.AudioTokens = usage.CompletionTokensDetails.AudioTokens
+	}
+
+	event := newRealtimeEvent(consts.REALTIME_EVENT_RESPONSE_DONE, model.RealtimeServerEvent{
+		Response: response,
+	})
+
+	g.realtimeResponseId = ""
+	g.realtimeOutput = nil
+
+	return event
+}
+
+func newRealtimeEvent(eventType string, event model.RealtimeServerEvent) []byte {
+
+	event.Type = eventType
+	event.EventId = "event_" + grand.S(24)
+
+	return gjson.MustEncode(event)
+}
+
+func convLiveUsageMetadata(usageMetadata *model.GoogleLiveUsageMetadata) *model.Usage {
+
+	usage := &model.Usage{
+		PromptTokens:     usageMetadata.PromptTokenCount + usageMetadata.ToolUsePromptTokenCount,
+		CompletionTokens: usageMetadata.ResponseTokenCount,
+		TotalTokens:      usageMetadata.TotalTokenCount,
+		OutputTokensDetails: model.OutputTokensDetails{
+			ReasoningTokens: usageMetadata.ThoughtsTokenCount,
+		},
+	}
+
+	usage.PromptTokensDetails.CachedTokens = usageMetadata.CachedContentTokenCount
+	usage.InputTokensDetails.CachedTokens = usageMetadata.CachedContentTokenCount
+
+	for _, promptTokensDetail := range usageMetadata.PromptTokensDetails {
+		switch promptTokensDetail.Modality {
+		case "TEXT":
+			usage.PromptTokensDetails.TextTokens = promptTokensDetail.TokenCount
+			usage.InputTokensDetails.TextTokens = promptTokensDetail.TokenCount
+		case "AUDIO":
+			usage.PromptTokensDetails.AudioTokens = promptTokensDetail.TokenCount
+			usage.InputTokensDetails.AudioTokens = promptTokensDetail.TokenCount
+		case "IMAGE", "VIDEO":
+			usage.PromptTokensDetails.ImageTokens += promptTokensDetail.TokenCount
+			usage.InputTokensDetails.ImageTokens += promptTokensDetail.TokenCount
+		}
+	}
+
+	for _, responseTokensDetail := range usageMetadata.ResponseTokensDetails {
+		switch responseTokensDetail.Modality {
+		case "TEXT":
+			usage.CompletionTokensDetails.TextTokens = responseTokensDetail.TokenCount
+		case "AUDIO":
+			usage.CompletionTokensDetails.AudioTokens = responseTokensDetail.TokenCount
+		}
+	}
+
+	return usage
+}
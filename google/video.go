@@ -2,36 +2,176 @@ package google
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (g *Google) VideoCreate(ctx context.Context, request model.VideoCreateRequest) (response model.VideoJobResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "VideoCreate Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "VideoCreate Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	data, err := g.ConvVideoCreateRequest(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "VideoCreate Google ConvVideoCreateRequest error: %v", err)
+		return response, err
+	}
+
+	bytes, responseHeader, err := g.VideoCreateOfficial(ctx, data.Bytes())
+	if err != nil {
+		logger.Errorf(ctx, "VideoCreate Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvVideoJobResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "VideoCreate Google ConvVideoJobResponse error: %v", err)
+		return response, err
+	}
+
+	response.CreatedAt = gtime.Timestamp()
+	response.Prompt = request.Prompt
+	response.Seconds = request.Seconds
+	response.Size = request.Size
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "VideoCreate Google model: %s finished, id: %s", g.Model, response.Id)
+
+	return response, nil
 }
 
+// Veo不支持基于已有视频的编辑, 不能用新生成的视频冒充
 func (g *Google) VideoRemix(ctx context.Context, request model.VideoRemixRequest) (response model.VideoJobResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("Google does not support remixing videos")
 }
 
 func (g *Google) VideoList(ctx context.Context, request model.VideoListRequest) (response model.VideoListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("Google does not support listing video operations")
 }
 
 func (g *Google) VideoRetrieve(ctx context.Context, request model.VideoRetrieveRequest) (response model.VideoJobResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "VideoRetrieve Google model: %s, videoId: %s start", g.Model, request.VideoId)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "VideoRetrieve Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	bytes, responseHeader, err := g.VideoRetrieveOfficial(ctx, request.VideoId)
+	if err != nil {
+		logger.Errorf(ctx, "VideoRetrieve Google model: %s, error: %v", g.Model, err)
+		return response, err
+	}
+
+	if response, err = g.ConvVideoJobResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "VideoRetrieve Google ConvVideoJobResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "VideoRetrieve Google model: %s, videoId: %s, status: %s finished", g.Model, request.VideoId, response.Status)
+
+	return response, nil
 }
 
 func (g *Google) VideoDelete(ctx context.Context, request model.VideoDeleteRequest) (response model.VideoJobResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("Google does not support deleting video operations")
 }
 
 func (g *Google) VideoContent(ctx context.Context, request model.VideoContentRequest) (response model.VideoContentResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "VideoContent Google model: %s, videoId: %s start", g.Model, request.VideoId)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "VideoContent Google model: %s totalTime: %d ms", g.Model, response.TotalTime)
+	}()
+
+	bytes, _, err := g.VideoRetrieveOfficial(ctx, request.VideoId)
+	if err != nil {
+		logger.Errorf(ctx, "VideoContent Google VideoRetrieveOfficial error: %v", err)
+		return response, err
+	}
+
+	retrieve, err := g.ConvVideoJobResponse(ctx, bytes)
+	if err != nil {
+		logger.Errorf(ctx, "VideoContent Google ConvVideoJobResponse error: %v", err)
+		return response, err
+	}
+
+	if retrieve.Status != "completed" {
+		return response, fmt.Errorf("VideoContent Google: video %s is not completed, status: %s", request.VideoId, retrieve.Status)
+	}
+
+	var data []byte
+
+	if retrieve.VideoUrl != "" {
+
+		videoUrl := retrieve.VideoUrl
+		if strings.HasPrefix(videoUrl, "gs://") {
+			videoUrl = "https://storage.googleapis.com/" + strings.TrimPrefix(videoUrl, "gs://")
+		}
+
+		// 下载地址需携带认证信息
+		if data, _, err = util.HttpGet(ctx, videoUrl, g.header, nil, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler); err != nil {
+			logger.Errorf(ctx, "VideoContent Google download error: %v", err)
+			return response, err
+		}
+
+	} else {
+
+		operation := model.GoogleVideoOperation{}
+		if err = json.Unmarshal(bytes, &operation); err != nil {
+			logger.Error(ctx, err)
+			return response, err
+		}
+
+		if operation.Response == nil || len(operation.Response.Videos) == 0 || operation.Response.Videos[0].BytesBase64Encoded == "" {
+			return response, fmt.Errorf("VideoContent Google: video content is empty for videoId %s", request.VideoId)
+		}
+
+		if data, err = base64.StdEncoding.DecodeString(operation.Response.Videos[0].BytesBase64Encoded); err != nil {
+			logger.Errorf(ctx, "VideoContent Google decode error: %v", err)
+			return response, err
+		}
+	}
+
+	if response, err = g.ConvVideoContentResponse(ctx, data); err != nil {
+		logger.Errorf(ctx, "VideoContent Google ConvVideoContentResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "VideoContent Google model: %s, videoId: %s finished, size: %d bytes", g.Model, request.VideoId, len(data))
+
+	return response, nil
+}
+
+// 视频任务完整的操作名称, 兼容只传id的情况
+func (g *Google) videoOperationName(videoId string) string {
+
+	if strings.Contains(videoId, "/operations/") {
+		return videoId
+	}
+
+	if g.isGcp && strings.Contains(g.Path, "/models/") {
+		return strings.TrimPrefix(g.Path, "/") + "/operations/" + videoId
+	}
+
+	return "models/" + g.Model + "/operations/" + videoId
 }
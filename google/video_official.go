@@ -2,27 +2,89 @@ package google
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (g *Google) VideoCreateOfficial(ctx context.Context, data []byte) (responseBytes []byte, responseHeader http.Header, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "VideoCreateOfficial Google model: %s start", g.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Infof(ctx, "VideoCreateOfficial Google model: %s totalTime: %d ms", g.Model, gtime.TimestampMilli()-now)
+	}()
+
+	if g.Path == "" {
+		g.Path = "/models/" + g.Model
+	}
+
+	if g.Action == "" {
+		g.Action = "predictLongRunning"
+	}
+
+	if responseBytes, responseHeader, err = util.HttpPost(ctx, fmt.Sprintf("%s%s:%s", g.BaseUrl, g.Path, g.Action), g.header, data, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler); err != nil {
+		logger.Errorf(ctx, "VideoCreateOfficial Google model: %s, error: %v", g.Model, err)
+		return nil, nil, err
+	}
+
+	logger.Infof(ctx, "VideoCreateOfficial Google model: %s finished", g.Model)
+
+	return responseBytes, responseHeader, nil
 }
 
 func (g *Google) VideoListOfficial(ctx context.Context, params model.VolcVideoListReq) (responseBytes []byte, responseHeader http.Header, err error) {
-	//TODO implement me
-	panic("implement me")
+	return nil, nil, errors.New("Google does not support listing video operations")
 }
 
 func (g *Google) VideoRetrieveOfficial(ctx context.Context, taskId string) (responseBytes []byte, responseHeader http.Header, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "VideoRetrieveOfficial Google model: %s, taskId: %s start", g.Model, taskId)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Infof(ctx, "VideoRetrieveOfficial Google model: %s totalTime: %d ms", g.Model, gtime.TimestampMilli()-now)
+	}()
+
+	if g.isGcp {
+
+		// Vertex需通过fetchPredictOperation查询长时间运行的操作
+		if g.Path == "" {
+			g.Path = "/models/" + g.Model
+		}
+
+		data := map[string]string{
+			"operationName": g.videoOperationName(taskId),
+		}
+
+		if responseBytes, responseHeader, err = util.HttpPost(ctx, fmt.Sprintf("%s%s:fetchPredictOperation", g.BaseUrl, g.Path), g.header, data, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler); err != nil {
+			logger.Errorf(ctx, "VideoRetrieveOfficial Google model: %s, error: %v", g.Model, err)
+			return nil, nil, err
+		}
+
+	} else {
+
+		if g.Path == "" {
+			g.Path = "/" + g.videoOperationName(taskId)
+		}
+
+		if responseBytes, responseHeader, err = util.HttpGet(ctx, g.BaseUrl+g.Path, g.header, nil, nil, g.Timeout, g.ProxyUrl, g.requestErrorHandler); err != nil {
+			logger.Errorf(ctx, "VideoRetrieveOfficial Google model: %s, error: %v", g.Model, err)
+			return nil, nil, err
+		}
+	}
+
+	logger.Infof(ctx, "VideoRetrieveOfficial Google model: %s, taskId: %s finished", g.Model, taskId)
+
+	return responseBytes, responseHeader, nil
 }
 
 func (g *Google) VideoDeleteOfficial(ctx context.Context, taskId string) (err error) {
-	//TODO implement me
-	panic("implement me")
+	return errors.New("Google does not support deleting video operations")
 }
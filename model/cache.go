@@ -0,0 +1,54 @@
+package model
+
+type CacheCreateRequest struct {
+	Model       string                  `json:"model"`
+	DisplayName string                  `json:"display_name,omitempty"`
+	Messages    []ChatCompletionMessage `json:"messages"`
+	Tools       any                     `json:"tools,omitempty"`
+	Ttl         int64                   `json:"ttl,omitempty"`        // 有效期, 单位: 秒
+	ExpiresAt   int64                   `json:"expires_at,omitempty"` // 过期时间戳, 单位: 秒, 与ttl二选一
+	Mode        string                  `json:"mode,omitempty"`       // 缓存模式, 如火山引擎的session、common_prefix
+}
+
+type CacheListRequest struct {
+	After string `json:"after"`
+	Limit int64  `json:"limit"`
+}
+
+type CacheListResponse struct {
+	Object        string          `json:"object"`
+	Data          []CacheResponse `json:"data"`
+	FirstId       *string         `json:"first_id"`
+	LastId        *string         `json:"last_id"`
+	HasMore       bool            `json:"has_more"`
+	ResponseBytes []byte          `json:"-"`
+	TotalTime     int64           `json:"-"`
+}
+
+type CacheRetrieveRequest struct {
+	CacheId string `json:"cache_id"`
+}
+
+type CacheUpdateRequest struct {
+	CacheId   string `json:"cache_id"`
+	Ttl       int64  `json:"ttl,omitempty"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+}
+
+type CacheDeleteRequest struct {
+	CacheId string `json:"cache_id"`
+}
+
+type CacheResponse struct {
+	Id            string `json:"id"`
+	Object        string `json:"object"`
+	Model         string `json:"model"`
+	DisplayName   string `json:"display_name,omitempty"`
+	CreatedAt     int64  `json:"created_at,omitempty"`
+	UpdatedAt     int64  `json:"updated_at,omitempty"`
+	ExpiresAt     int64  `json:"expires_at,omitempty"`
+	Usage         *Usage `json:"usage,omitempty"`
+	Deleted       bool   `json:"deleted,omitempty"`
+	ResponseBytes []byte `json:"-"`
+	TotalTime     int64  `json:"-"`
+}
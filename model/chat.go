@@ -35,6 +35,8 @@ type ChatCompletionRequest struct {
 	Audio               *Audio                        `json:"audio,omitempty"`
 	WebSearchOptions    any                           `json:"web_search_options,omitempty"`
 	EnableThinking      *bool                         `json:"enable_thinking,omitempty"`
+	SafetySettings      []SafetySetting               `json:"safety_settings,omitempty"`
+	CacheId             string                        `json:"cache_id,omitempty"` // 引用的上下文缓存, 如Google的cachedContents
 }
 
 type ChatCompletionResponse struct {
@@ -72,11 +74,12 @@ type ChatCompletionMessage struct {
 }
 
 type ChatCompletionChoice struct {
-	Index        int                              `json:"index"`
-	Message      *ChatCompletionMessage           `json:"message,omitempty"`
-	Delta        *ChatCompletionStreamChoiceDelta `json:"delta,omitempty"`
-	LogProbs     *LogProbs                        `json:"logprobs"`
-	FinishReason string                           `json:"finish_reason"`
+	Index                int                              `json:"index"`
+	Message              *ChatCompletionMessage           `json:"message,omitempty"`
+	Delta                *ChatCompletionStreamChoiceDelta `json:"delta,omitempty"`
+	LogProbs             *LogProbs                        `json:"logprobs"`
+	FinishReason         string                           `json:"finish_reason"`
+	ContentFilterResults *ContentFilterResults            `json:"content_filter_results,omitempty"`
 }
 
 type Usage struct {
@@ -119,6 +122,7 @@ type CompletionTokensDetails struct {
 
 type InputTokensDetails struct {
 	TextTokens       int `json:"text_tokens,omitempty"`
+	AudioTokens      int `json:"audio_tokens,omitempty"`
 	ImageTokens      int `json:"image_tokens,omitempty"`
 	CachedTokens     int `json:"cached_tokens,omitempty"`
 	CacheWriteTokens int `json:"cache_write_tokens,omitempty"`
@@ -132,6 +136,7 @@ type OutputTokensDetails struct {
 
 type ChatCompletionStreamChoiceDelta struct {
 	Content          string        `json:"content"`
+	ContentParts     []any         `json:"content_parts,omitempty"` // 包含图片等非文本内容时的多模态内容块, content仅保留文本
 	ReasoningContent any           `json:"reasoning_content,omitempty"`
 	Role             string        `json:"role,omitempty"`
 	FunctionCall     *FunctionCall `json:"function_call,omitempty"`
@@ -166,6 +171,12 @@ type Audio struct {
 	Transcript string `json:"transcript,omitempty"`
 }
 
+// 安全设置, 如Google的safetySettings
+type SafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
 type PromptAnnotation struct {
 	PromptIndex          int                  `json:"prompt_index,omitempty"`
 	ContentFilterResults ContentFilterResults `json:"content_filter_results,omitempty"`
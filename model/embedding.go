@@ -10,6 +10,24 @@ type EmbeddingRequest struct {
 	// Dimensions The number of dimensions the resulting output embeddings should have.
 	// Only supported in text-embedding-3 and later models.
 	Dimensions int `json:"dimensions,omitempty"`
+	// TaskType 嵌入的任务类型, 如Google的RETRIEVAL_QUERY、RETRIEVAL_DOCUMENT、SEMANTIC_SIMILARITY等
+	TaskType string `json:"task_type,omitempty"`
+	// Title 文档标题, 仅在Google的taskType为RETRIEVAL_DOCUMENT时有效
+	Title string `json:"title,omitempty"`
+}
+
+// EmbeddingInput 多模态嵌入的输入项, 如火山引擎的doubao-embedding-vision
+type EmbeddingInput struct {
+	// Type 输入类型, 如text、image_url、video_url
+	Type     string             `json:"type"`
+	Text     string             `json:"text,omitempty"`
+	ImageUrl *EmbeddingMediaUrl `json:"image_url,omitempty"`
+	VideoUrl *EmbeddingMediaUrl `json:"video_url,omitempty"`
+}
+
+type EmbeddingMediaUrl struct {
+	// Url 支持URL或Base64编码
+	Url string `json:"url"`
 }
 
 type EmbeddingResponse struct {
@@ -1,21 +1,28 @@
 package model
 
 import (
+	"encoding/json"
 	"net/http"
 	"time"
 )
 
 type GoogleChatCompletionReq struct {
-	Contents         []Content        `json:"contents"`
-	GenerationConfig GenerationConfig `json:"generationConfig,omitempty"`
-	Tools            any              `json:"tools,omitempty"`
+	Model             string           `json:"model,omitempty"` // 批处理输入文件中每行请求的模型, 格式为 models/{model}
+	Contents          []Content        `json:"contents"`
+	GenerationConfig  GenerationConfig `json:"generationConfig,omitempty"`
+	Tools             any              `json:"tools,omitempty"`
+	SafetySettings    []SafetySetting  `json:"safetySettings,omitempty"`
+	SystemInstruction *Content         `json:"systemInstruction,omitempty"`
+	CachedContent     string           `json:"cachedContent,omitempty"`
 }
 
 type GoogleChatCompletionRes struct {
-	Candidates    []Candidate    `json:"candidates"`
-	UsageMetadata *UsageMetadata `json:"usageMetadata"`
-	ModelVersion  string         `json:"modelVersion"`
-	Error         struct {
+	Candidates     []Candidate     `json:"candidates"`
+	PromptFeedback *PromptFeedback `json:"promptFeedback,omitempty"`
+	UsageMetadata  *UsageMetadata  `json:"usageMetadata,omitempty"`
+	ModelVersion   string          `json:"modelVersion,omitempty"`
+	ResponseId     string          `json:"responseId,omitempty"`
+	Error          struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
 		Status  string `json:"status"`
@@ -27,7 +34,7 @@ type GoogleChatCompletionRes struct {
 				Service string `json:"service"`
 			} `json:"metadata"`
 		} `json:"details"`
-	} `json:"error"`
+	} `json:"error,omitzero"`
 	ResponseBytes   []byte      `json:"-"`
 	ResponseHeaders http.Header `json:"-"`
 	ConnTime        int64       `json:"-"`
@@ -42,60 +49,128 @@ type Content struct {
 }
 
 type Part struct {
-	Text             string      `json:"text,omitempty"`
-	InlineData       *InlineData `json:"inlineData,omitempty"`
-	FileData         *FileData   `json:"fileData,omitempty"`
-	FunctionCall     any         `json:"functionCall,omitempty"`
-	FunctionResponse any         `json:"functionResponse,omitempty"`
-	ThoughtSignature any         `json:"thoughtSignature,omitempty"`
+	Text                string               `json:"text,omitempty"`
+	InlineData          *InlineData          `json:"inlineData,omitempty"`
+	FileData            *FileData            `json:"fileData,omitempty"`
+	FunctionCall        any                  `json:"functionCall,omitempty"`
+	FunctionResponse    any                  `json:"functionResponse,omitempty"`
+	ExecutableCode      *ExecutableCode      `json:"executableCode,omitempty"`
+	CodeExecutionResult *CodeExecutionResult `json:"codeExecutionResult,omitempty"`
+	Thought             bool                 `json:"thought,omitempty"`
+	ThoughtSignature    any                  `json:"thoughtSignature,omitempty"`
 }
 
 type InlineData struct {
-	MimeType string `json:"mime_type,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
 	Data     string `json:"data,omitempty"`
 }
 
 type FileData struct {
-	FileUri  string `json:"file_uri,omitempty"`
-	MimeType string `json:"mime_type,omitempty"`
+	FileUri  string `json:"fileUri,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+type ExecutableCode struct {
+	Language string `json:"language,omitempty"`
+	Code     string `json:"code,omitempty"`
+}
+
+type CodeExecutionResult struct {
+	Outcome string `json:"outcome,omitempty"`
+	Output  string `json:"output,omitempty"`
 }
 
 type Candidate struct {
-	Content       Content        `json:"content"`
-	FinishReason  string         `json:"finishReason"`
-	Index         int            `json:"index"`
-	SafetyRatings []SafetyRating `json:"safetyRatings"`
+	Content        Content         `json:"content"`
+	FinishReason   string          `json:"finishReason"`
+	Index          int             `json:"index"`
+	SafetyRatings  []SafetyRating  `json:"safetyRatings,omitempty"`
+	AvgLogprobs    float64         `json:"avgLogprobs,omitempty"`
+	LogprobsResult *LogprobsResult `json:"logprobsResult,omitempty"`
+}
+
+type LogprobsResult struct {
+	TopCandidates    []LogprobsTopCandidates `json:"topCandidates,omitempty"`
+	ChosenCandidates []LogprobsCandidate     `json:"chosenCandidates,omitempty"`
+}
+
+type LogprobsTopCandidates struct {
+	Candidates []LogprobsCandidate `json:"candidates"`
+}
+
+type LogprobsCandidate struct {
+	Token          string  `json:"token"`
+	TokenId        int     `json:"tokenId,omitempty"`
+	LogProbability float64 `json:"logProbability"`
 }
 
 type SafetyRating struct {
-	Category    string `json:"category"`
-	Probability string `json:"probability"`
+	Category         string  `json:"category"`
+	Probability      string  `json:"probability"`
+	ProbabilityScore float64 `json:"probabilityScore,omitempty"`
+	Severity         string  `json:"severity,omitempty"`
+	SeverityScore    float64 `json:"severityScore,omitempty"`
+	Blocked          bool    `json:"blocked,omitempty"`
+}
+
+type PromptFeedback struct {
+	BlockReason        string         `json:"blockReason,omitempty"`
+	BlockReasonMessage string         `json:"blockReasonMessage,omitempty"`
+	SafetyRatings      []SafetyRating `json:"safetyRatings,omitempty"`
 }
 
 type UsageMetadata struct {
-	PromptTokenCount     int `json:"promptTokenCount"`
-	CandidatesTokenCount int `json:"candidatesTokenCount"`
-	TotalTokenCount      int `json:"totalTokenCount"`
-	PromptTokensDetails  []struct {
-		Modality   string `json:"modality"`
-		TokenCount int    `json:"tokenCount"`
-	} `json:"promptTokensDetails"`
-	CandidatesTokensDetails []struct {
-		Modality   string `json:"modality"`
-		TokenCount int    `json:"tokenCount"`
-	} `json:"candidatesTokensDetails"`
-	ThoughtsTokenCount int `json:"thoughtsTokenCount"`
+	PromptTokenCount        int                  `json:"promptTokenCount"`
+	CandidatesTokenCount    int                  `json:"candidatesTokenCount"`
+	TotalTokenCount         int                  `json:"totalTokenCount"`
+	PromptTokensDetails     []ModalityTokenCount `json:"promptTokensDetails,omitempty"`
+	CandidatesTokensDetails []ModalityTokenCount `json:"candidatesTokensDetails,omitempty"`
+	ThoughtsTokenCount      int                  `json:"thoughtsTokenCount,omitempty"`
+	CachedContentTokenCount int                  `json:"cachedContentTokenCount,omitempty"`
+}
+
+type ModalityTokenCount struct {
+	Modality   string `json:"modality"`
+	TokenCount int    `json:"tokenCount"`
 }
 
 type GenerationConfig struct {
-	StopSequences      []string     `json:"stopSequences,omitempty"`
-	CandidateCount     int          `json:"candidateCount,omitempty"`
-	MaxOutputTokens    int          `json:"maxOutputTokens,omitempty"`
-	Temperature        float32      `json:"temperature,omitempty"`
-	TopP               float32      `json:"topP,omitempty"`
-	TopK               int          `json:"topK,omitempty"`
-	ResponseModalities []string     `json:"responseModalities,omitempty"`
-	ImageConfig        *ImageConfig `json:"imageConfig,omitempty"`
+	StopSequences      []string        `json:"stopSequences,omitempty"`
+	CandidateCount     int             `json:"candidateCount,omitempty"`
+	MaxOutputTokens    int             `json:"maxOutputTokens,omitempty"`
+	Temperature        float32         `json:"temperature,omitempty"`
+	TopP               float32         `json:"topP,omitempty"`
+	TopK               int             `json:"topK,omitempty"`
+	PresencePenalty    float32         `json:"presencePenalty,omitempty"`
+	FrequencyPenalty   float32         `json:"frequencyPenalty,omitempty"`
+	Seed               *int            `json:"seed,omitempty"`
+	ResponseMimeType   string          `json:"responseMimeType,omitempty"`
+	ResponseSchema     any             `json:"responseSchema,omitempty"`
+	ResponseLogprobs   bool            `json:"responseLogprobs,omitempty"`
+	Logprobs           int             `json:"logprobs,omitempty"`
+	ResponseModalities []string        `json:"responseModalities,omitempty"`
+	ImageConfig        *ImageConfig    `json:"imageConfig,omitempty"`
+	ThinkingConfig     *ThinkingConfig `json:"thinkingConfig,omitempty"`
+	SpeechConfig       *SpeechConfig   `json:"speechConfig,omitempty"`
+}
+
+type SpeechConfig struct {
+	VoiceConfig  *VoiceConfig `json:"voiceConfig,omitempty"`
+	LanguageCode string       `json:"languageCode,omitempty"`
+}
+
+type VoiceConfig struct {
+	PrebuiltVoiceConfig *PrebuiltVoiceConfig `json:"prebuiltVoiceConfig,omitempty"`
+}
+
+type PrebuiltVoiceConfig struct {
+	VoiceName string `json:"voiceName,omitempty"`
+}
+
+type ThinkingConfig struct {
+	IncludeThoughts bool   `json:"includeThoughts,omitempty"`
+	ThinkingBudget  *int   `json:"thinkingBudget,omitempty"`
+	ThinkingLevel   string `json:"thinkingLevel,omitempty"`
 }
 
 type ImageConfig struct {
@@ -128,8 +203,268 @@ type GoogleFile struct {
 	Source string `json:"source"`
 }
 
+type GoogleCachedContent struct {
+	Name              string     `json:"name,omitempty"`
+	DisplayName       string     `json:"displayName,omitempty"`
+	Model             string     `json:"model,omitempty"`
+	SystemInstruction *Content   `json:"systemInstruction,omitempty"`
+	Contents          []Content  `json:"contents,omitempty"`
+	Tools             any        `json:"tools,omitempty"`
+	Ttl               string     `json:"ttl,omitempty"`
+	ExpireTime        *time.Time `json:"expireTime,omitempty"`
+	CreateTime        *time.Time `json:"createTime,omitempty"`
+	UpdateTime        *time.Time `json:"updateTime,omitempty"`
+	UsageMetadata     *struct {
+		TotalTokenCount int `json:"totalTokenCount"`
+	} `json:"usageMetadata,omitempty"`
+}
+
+type GoogleCachedContentListResponse struct {
+	CachedContents []GoogleCachedContent `json:"cachedContents"`
+	NextPageToken  string                `json:"nextPageToken"`
+}
+
+type GoogleEmbeddingReq struct {
+	Requests []GoogleEmbedContentReq `json:"requests"`
+}
+
+type GoogleEmbedContentReq struct {
+	Model                string  `json:"model"`
+	Content              Content `json:"content"`
+	TaskType             string  `json:"taskType,omitempty"`
+	Title                string  `json:"title,omitempty"`
+	OutputDimensionality int     `json:"outputDimensionality,omitempty"`
+}
+
+type GoogleEmbeddingRes struct {
+	Embeddings []struct {
+		Values []float64 `json:"values"`
+	} `json:"embeddings"`
+	UsageMetadata *UsageMetadata `json:"usageMetadata,omitempty"`
+}
+
+type GoogleVertexEmbeddingReq struct {
+	Instances  []GoogleVertexEmbeddingInstance  `json:"instances"`
+	Parameters *GoogleVertexEmbeddingParameters `json:"parameters,omitempty"`
+}
+
+type GoogleVertexEmbeddingInstance struct {
+	Content  string `json:"content"`
+	TaskType string `json:"task_type,omitempty"`
+	Title    string `json:"title,omitempty"`
+}
+
+type GoogleVertexEmbeddingParameters struct {
+	OutputDimensionality int  `json:"outputDimensionality,omitempty"`
+	AutoTruncate         bool `json:"autoTruncate"`
+}
+
+type GoogleVertexEmbeddingRes struct {
+	Predictions []struct {
+		Embeddings struct {
+			Values     []float64 `json:"values"`
+			Statistics struct {
+				TokenCount int  `json:"token_count"`
+				Truncated  bool `json:"truncated"`
+			} `json:"statistics"`
+		} `json:"embeddings"`
+	} `json:"predictions"`
+}
+
+type GoogleBatchCreateReq struct {
+	Batch GoogleBatch `json:"batch"`
+}
+
+type GoogleBatch struct {
+	Name        string `json:"name,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Model       string `json:"model,omitempty"`
+	InputConfig *struct {
+		FileName string `json:"fileName,omitempty"`
+	} `json:"inputConfig,omitempty"`
+	Output *struct {
+		ResponsesFile string `json:"responsesFile,omitempty"`
+	} `json:"output,omitempty"`
+	CreateTime *time.Time `json:"createTime,omitempty"`
+	UpdateTime *time.Time `json:"updateTime,omitempty"`
+	EndTime    *time.Time `json:"endTime,omitempty"`
+	BatchStats *struct {
+		RequestCount           any `json:"requestCount,omitempty"`
+		SuccessfulRequestCount any `json:"successfulRequestCount,omitempty"`
+		FailedRequestCount     any `json:"failedRequestCount,omitempty"`
+		PendingRequestCount    any `json:"pendingRequestCount,omitempty"`
+	} `json:"batchStats,omitempty"`
+	State string `json:"state,omitempty"`
+}
+
+type GoogleBatchOperation struct {
+	Name     string      `json:"name"`
+	Metadata GoogleBatch `json:"metadata"`
+	Done     bool        `json:"done"`
+	Response *struct {
+		ResponsesFile string `json:"responsesFile,omitempty"`
+	} `json:"response,omitempty"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type GoogleBatchListResponse struct {
+	Operations    []GoogleBatchOperation `json:"operations"`
+	NextPageToken string                 `json:"nextPageToken"`
+}
+
+type GoogleBatchOutput struct {
+	Key      string          `json:"key"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type GoogleVideoReq struct {
+	Instances  []GoogleVideoInstance  `json:"instances"`
+	Parameters *GoogleVideoParameters `json:"parameters,omitempty"`
+}
+
+type GoogleVideoInstance struct {
+	Prompt string            `json:"prompt,omitempty"`
+	Image  *GoogleVideoMedia `json:"image,omitempty"`
+}
+
+type GoogleVideoMedia struct {
+	BytesBase64Encoded string `json:"bytesBase64Encoded,omitempty"`
+	GcsUri             string `json:"gcsUri,omitempty"`
+	MimeType           string `json:"mimeType,omitempty"`
+}
+
+type GoogleVideoParameters struct {
+	AspectRatio      string `json:"aspectRatio,omitempty"`
+	Resolution       string `json:"resolution,omitempty"`
+	DurationSeconds  int    `json:"durationSeconds,omitempty"`
+	NegativePrompt   string `json:"negativePrompt,omitempty"`
+	PersonGeneration string `json:"personGeneration,omitempty"`
+	SampleCount      int    `json:"sampleCount,omitempty"`
+}
+
+type GoogleVideoOperation struct {
+	Name     string         `json:"name"`
+	Done     bool           `json:"done"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Response *struct {
+		// Gemini API
+		GenerateVideoResponse *struct {
+			GeneratedSamples []struct {
+				Video struct {
+					Uri string `json:"uri"`
+				} `json:"video"`
+			} `json:"generatedSamples"`
+			RaiMediaFilteredCount   int      `json:"raiMediaFilteredCount,omitempty"`
+			RaiMediaFilteredReasons []string `json:"raiMediaFilteredReasons,omitempty"`
+		} `json:"generateVideoResponse,omitempty"`
+		// Vertex AI
+		Videos                  []GoogleVideoMedia `json:"videos,omitempty"`
+		RaiMediaFilteredCount   int                `json:"raiMediaFilteredCount,omitempty"`
+		RaiMediaFilteredReasons []string           `json:"raiMediaFilteredReasons,omitempty"`
+	} `json:"response,omitempty"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
 type GoogleImageGenerationReq struct {
 	Contents         []Content        `json:"contents"`
 	Tools            any              `json:"tools,omitempty"`
 	GenerationConfig GenerationConfig `json:"generationConfig,omitempty"`
 }
+
+type GoogleLiveClientMessage struct {
+	Setup         *GoogleLiveSetup         `json:"setup,omitempty"`
+	ClientContent *GoogleLiveClientContent `json:"clientContent,omitempty"`
+	RealtimeInput *GoogleLiveRealtimeInput `json:"realtimeInput,omitempty"`
+	ToolResponse  *GoogleLiveToolResponse  `json:"toolResponse,omitempty"`
+}
+
+type GoogleLiveSetup struct {
+	Model                    string            `json:"model"`
+	GenerationConfig         *GenerationConfig `json:"generationConfig,omitempty"`
+	SystemInstruction        *Content          `json:"systemInstruction,omitempty"`
+	Tools                    any               `json:"tools,omitempty"`
+	InputAudioTranscription  *struct{}         `json:"inputAudioTranscription,omitempty"`
+	OutputAudioTranscription *struct{}         `json:"outputAudioTranscription,omitempty"`
+}
+
+type GoogleLiveClientContent struct {
+	Turns        []Content `json:"turns,omitempty"`
+	TurnComplete bool      `json:"turnComplete"`
+}
+
+type GoogleLiveRealtimeInput struct {
+	Audio          *InlineData `json:"audio,omitempty"`
+	Video          *InlineData `json:"video,omitempty"`
+	Text           string      `json:"text,omitempty"`
+	AudioStreamEnd bool        `json:"audioStreamEnd,omitempty"`
+}
+
+type GoogleLiveToolResponse struct {
+	FunctionResponses []GoogleLiveFunctionResponse `json:"functionResponses"`
+}
+
+type GoogleLiveFunctionResponse struct {
+	Id       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Response any    `json:"response"`
+}
+
+type GoogleLiveServerMessage struct {
+	SetupComplete        *struct{}                `json:"setupComplete,omitempty"`
+	ServerContent        *GoogleLiveServerContent `json:"serverContent,omitempty"`
+	ToolCall             *GoogleLiveToolCall      `json:"toolCall,omitempty"`
+	ToolCallCancellation *struct {
+		Ids []string `json:"ids"`
+	} `json:"toolCallCancellation,omitempty"`
+	GoAway *struct {
+		TimeLeft string `json:"timeLeft"`
+	} `json:"goAway,omitempty"`
+	UsageMetadata *GoogleLiveUsageMetadata `json:"usageMetadata,omitempty"`
+	Error         *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error,omitempty"`
+}
+
+type GoogleLiveServerContent struct {
+	ModelTurn          *Content `json:"modelTurn,omitempty"`
+	TurnComplete       bool     `json:"turnComplete,omitempty"`
+	Interrupted        bool     `json:"interrupted,omitempty"`
+	GenerationComplete bool     `json:"generationComplete,omitempty"`
+	InputTranscription *struct {
+		Text string `json:"text"`
+	} `json:"inputTranscription,omitempty"`
+	OutputTranscription *struct {
+		Text string `json:"text"`
+	} `json:"outputTranscription,omitempty"`
+}
+
+type GoogleLiveToolCall struct {
+	FunctionCalls []struct {
+		Id   string         `json:"id"`
+		Name string         `json:"name"`
+		Args map[string]any `json:"args"`
+	} `json:"functionCalls"`
+}
+
+type GoogleLiveUsageMetadata struct {
+	PromptTokenCount        int                  `json:"promptTokenCount"`
+	CachedContentTokenCount int                  `json:"cachedContentTokenCount,omitempty"`
+	ResponseTokenCount      int                  `json:"responseTokenCount"`
+	ToolUsePromptTokenCount int                  `json:"toolUsePromptTokenCount,omitempty"`
+	ThoughtsTokenCount      int                  `json:"thoughtsTokenCount,omitempty"`
+	TotalTokenCount         int                  `json:"totalTokenCount"`
+	PromptTokensDetails     []ModalityTokenCount `json:"promptTokensDetails,omitempty"`
+	ResponseTokensDetails   []ModalityTokenCount `json:"responseTokensDetails,omitempty"`
+}
@@ -23,6 +23,10 @@ type ImageGenerationRequest struct {
 	Stream            bool             `json:"stream,omitempty"`
 	Image             any              `json:"image,omitempty"`
 	Images            []ImageEditImage `json:"images,omitempty"`
+	Seed              *int             `json:"seed,omitempty"`                        // 随机种子, 如火山引擎的Seedream
+	GuidanceScale     float32          `json:"guidance_scale,omitempty"`              // 提示词的权重, 如火山引擎的Seedream 3.0/SeedEdit 3.0
+	Sequential        string           `json:"sequential_image_generation,omitempty"` // 组图生成模式, 如火山引擎的auto/disabled
+	Watermark         *bool            `json:"watermark,omitempty"`                   // 是否添加水印, 如火山引擎默认添加
 }
 
 type ImageResponse struct {
@@ -143,4 +147,8 @@ type ImageEditRequest struct {
 	User              string           `json:"user,omitempty"`
 	AspectRatio       string           `json:"aspect_ratio,omitempty"`
 	Stream            bool             `json:"stream,omitempty"`
+	Seed              *int             `json:"seed,omitempty"`                        // 随机种子, 如火山引擎的Seedream
+	GuidanceScale     float32          `json:"guidance_scale,omitempty"`              // 提示词的权重, 如火山引擎的Seedream 3.0/SeedEdit 3.0
+	Sequential        string           `json:"sequential_image_generation,omitempty"` // 组图生成模式, 如火山引擎的auto/disabled
+	Watermark         *bool            `json:"watermark,omitempty"`                   // 是否添加水印, 如火山引擎默认添加
 }
@@ -9,8 +9,169 @@ type RealtimeResponse struct {
 	MessageType int    `json:"message_type"`
 	Message     []byte `json:"message"`
 	Usage       *Usage `json:"usage"`
+	CloseCode   int    `json:"-"`
 	ConnTime    int64  `json:"-"`
 	Duration    int64  `json:"-"`
 	TotalTime   int64  `json:"-"`
 	Error       error  `json:"-"`
 }
+
+type RealtimeClientEvent struct {
+	EventId        string                    `json:"event_id,omitempty"`
+	Type           string                    `json:"type"`
+	Session        *RealtimeSession          `json:"session,omitempty"`
+	Audio          string                    `json:"audio,omitempty"`
+	PreviousItemId string                    `json:"previous_item_id,omitempty"`
+	Item           *RealtimeConversationItem `json:"item,omitempty"`
+	ItemId         string                    `json:"item_id,omitempty"`
+	ContentIndex   *int                      `json:"content_index,omitempty"`
+	AudioEndMs     *int                      `json:"audio_end_ms,omitempty"`
+	Response       *RealtimeResponseConfig   `json:"response,omitempty"`
+	ResponseId     string                    `json:"response_id,omitempty"`
+}
+
+type RealtimeServerEvent struct {
+	EventId        string                    `json:"event_id"`
+	Type           string                    `json:"type"`
+	Session        *RealtimeSession          `json:"session,omitempty"`
+	PreviousItemId string                    `json:"previous_item_id,omitempty"`
+	Item           *RealtimeConversationItem `json:"item,omitempty"`
+	ItemId         string                    `json:"item_id,omitempty"`
+	Response       *RealtimeResponseObject   `json:"response,omitempty"`
+	ResponseId     string                    `json:"response_id,omitempty"`
+	OutputIndex    int                       `json:"output_index,omitempty"`
+	ContentIndex   int                       `json:"content_index,omitempty"`
+	Part           *RealtimeContentPart      `json:"part,omitempty"`
+	Delta          string                    `json:"delta,omitempty"`
+	Text           string                    `json:"text,omitempty"`
+	Transcript     string                    `json:"transcript,omitempty"`
+	CallId         string                    `json:"call_id,omitempty"`
+	Name           string                    `json:"name,omitempty"`
+	Arguments      string                    `json:"arguments,omitempty"`
+	AudioStartMs   int                       `json:"audio_start_ms,omitempty"`
+	AudioEndMs     int                       `json:"audio_end_ms,omitempty"`
+	RateLimits     []RealtimeRateLimit       `json:"rate_limits,omitempty"`
+	Error          *RealtimeError            `json:"error,omitempty"`
+}
+
+type RealtimeSession struct {
+	Id                      string                           `json:"id,omitempty"`
+	Object                  string                           `json:"object,omitempty"`
+	Model                   string                           `json:"model,omitempty"`
+	Modalities              []string                         `json:"modalities,omitempty"`
+	Instructions            string                           `json:"instructions,omitempty"`
+	Voice                   string                           `json:"voice,omitempty"`
+	InputAudioFormat        string                           `json:"input_audio_format,omitempty"`
+	OutputAudioFormat       string                           `json:"output_audio_format,omitempty"`
+	InputAudioTranscription *RealtimeInputAudioTranscription `json:"input_audio_transcription,omitempty"`
+	TurnDetection           *RealtimeTurnDetection           `json:"turn_detection,omitempty"`
+	Tools                   []RealtimeTool                   `json:"tools,omitempty"`
+	ToolChoice              any                              `json:"tool_choice,omitempty"`
+	Temperature             *float64                         `json:"temperature,omitempty"`
+	MaxResponseOutputTokens any                              `json:"max_response_output_tokens,omitempty"`
+}
+
+type RealtimeInputAudioTranscription struct {
+	Model    string `json:"model,omitempty"`
+	Language string `json:"language,omitempty"`
+	Prompt   string `json:"prompt,omitempty"`
+}
+
+type RealtimeTurnDetection struct {
+	Type              string   `json:"type,omitempty"`
+	Threshold         *float64 `json:"threshold,omitempty"`
+	PrefixPaddingMs   *int     `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs *int     `json:"silence_duration_ms,omitempty"`
+	Eagerness         string   `json:"eagerness,omitempty"`
+	CreateResponse    *bool    `json:"create_response,omitempty"`
+	InterruptResponse *bool    `json:"interrupt_response,omitempty"`
+}
+
+type RealtimeTool struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+type RealtimeConversationItem struct {
+	Id        string                `json:"id,omitempty"`
+	Object    string                `json:"object,omitempty"`
+	Type      string                `json:"type"`
+	Status    string                `json:"status,omitempty"`
+	Role      string                `json:"role,omitempty"`
+	Content   []RealtimeContentPart `json:"content,omitempty"`
+	CallId    string                `json:"call_id,omitempty"`
+	Name      string                `json:"name,omitempty"`
+	Arguments string                `json:"arguments,omitempty"`
+	Output    string                `json:"output,omitempty"`
+}
+
+type RealtimeContentPart struct {
+	Type       string `json:"type"`
+	Text       string `json:"text,omitempty"`
+	Audio      string `json:"audio,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+}
+
+type RealtimeResponseConfig struct {
+	Modalities              []string                   `json:"modalities,omitempty"`
+	Instructions            string                     `json:"instructions,omitempty"`
+	Voice                   string                     `json:"voice,omitempty"`
+	OutputAudioFormat       string                     `json:"output_audio_format,omitempty"`
+	Tools                   []RealtimeTool             `json:"tools,omitempty"`
+	ToolChoice              any                        `json:"tool_choice,omitempty"`
+	Temperature             *float64                   `json:"temperature,omitempty"`
+	MaxResponseOutputTokens any                        `json:"max_response_output_tokens,omitempty"`
+	Conversation            string                     `json:"conversation,omitempty"`
+	Metadata                map[string]string          `json:"metadata,omitempty"`
+	Input                   []RealtimeConversationItem `json:"input,omitempty"`
+}
+
+type RealtimeResponseObject struct {
+	Id            string                     `json:"id"`
+	Object        string                     `json:"object"`
+	Status        string                     `json:"status"`
+	StatusDetails any                        `json:"status_details,omitempty"`
+	Output        []RealtimeConversationItem `json:"output"`
+	Metadata      map[string]string          `json:"metadata,omitempty"`
+	Usage         *RealtimeUsage             `json:"usage,omitempty"`
+}
+
+type RealtimeUsage struct {
+	TotalTokens        int                             `json:"total_tokens"`
+	InputTokens        int                             `json:"input_tokens"`
+	OutputTokens       int                             `json:"output_tokens"`
+	InputTokenDetails  RealtimeUsageInputTokenDetails  `json:"input_token_details"`
+	OutputTokenDetails RealtimeUsageOutputTokenDetails `json:"output_token_details"`
+}
+
+type RealtimeUsageInputTokenDetails struct {
+	CachedTokens        int `json:"cached_tokens"`
+	TextTokens          int `json:"text_tokens"`
+	AudioTokens         int `json:"audio_tokens"`
+	CachedTokensDetails struct {
+		TextTokens  int `json:"text_tokens"`
+		AudioTokens int `json:"audio_tokens"`
+	} `json:"cached_tokens_details"`
+}
+
+type RealtimeUsageOutputTokenDetails struct {
+	TextTokens  int `json:"text_tokens"`
+	AudioTokens int `json:"audio_tokens"`
+}
+
+type RealtimeRateLimit struct {
+	Name         string  `json:"name"`
+	Limit        int     `json:"limit"`
+	Remaining    int     `json:"remaining"`
+	ResetSeconds float64 `json:"reset_seconds"`
+}
+
+type RealtimeError struct {
+	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
+	EventId string `json:"event_id,omitempty"`
+}
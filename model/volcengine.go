@@ -123,3 +123,217 @@ type VolcVideoListRes struct {
 	Items []*VolcVideoTaskRes `json:"items"`
 	Total int                 `json:"total"`
 }
+
+// ---- 火山引擎图片生成 API 数据结构 ----
+
+// VolcImageReq 图片生成请求（文生图/图生图共用）
+type VolcImageReq struct {
+	Model                            string                                `json:"model"`                                         // 模型 ID
+	Prompt                           string                                `json:"prompt"`                                        // 提示词
+	Image                            any                                   `json:"image,omitempty"`                               // 参考图片, 单张为字符串, 多张为数组（URL / Base64 编码）
+	Size                             string                                `json:"size,omitempty"`                                // 1K / 2K / 4K 或 宽x高
+	Seed                             *int                                  `json:"seed,omitempty"`                                // 随机种子
+	SequentialImageGeneration        string                                `json:"sequential_image_generation,omitempty"`         // auto / disabled
+	SequentialImageGenerationOptions *VolcSequentialImageGenerationOptions `json:"sequential_image_generation_options,omitempty"` // 组图配置
+	Stream                           bool                                  `json:"stream,omitempty"`                              // 是否流式输出
+	GuidanceScale                    float32                               `json:"guidance_scale,omitempty"`                      // 提示词的权重
+	ResponseFormat                   string                                `json:"response_format,omitempty"`                     // url / b64_json
+	Watermark                        *bool                                 `json:"watermark,omitempty"`                           // 是否添加水印
+}
+
+// VolcSequentialImageGenerationOptions 组图配置
+type VolcSequentialImageGenerationOptions struct {
+	MaxImages int `json:"max_images,omitempty"` // 最多生成图片数量
+}
+
+// VolcImageData 生成的图片
+type VolcImageData struct {
+	Url     string          `json:"url,omitempty"`      // 图片 URL, 24 小时内有效
+	B64Json string          `json:"b64_json,omitempty"` // 图片 Base64 编码
+	Size    string          `json:"size,omitempty"`     // 图片宽高, 如 2048x2048
+	Error   *VolcVideoError `json:"error,omitempty"`    // 单张图片生成失败时的错误信息
+}
+
+// VolcImageUsage 图片生成用量
+type VolcImageUsage struct {
+	GeneratedImages int `json:"generated_images"` // 成功生成的图片数量
+	OutputTokens    int `json:"output_tokens"`
+	TotalTokens     int `json:"total_tokens"`
+}
+
+// VolcImageRes 图片生成响应
+type VolcImageRes struct {
+	Model   string          `json:"model"`
+	Created int64           `json:"created"`
+	Data    []VolcImageData `json:"data"`
+	Usage   *VolcImageUsage `json:"usage,omitempty"`
+	Error   *VolcVideoError `json:"error,omitempty"`
+}
+
+// VolcImageStreamRes 图片生成流式响应
+type VolcImageStreamRes struct {
+	Type       string          `json:"type"` // image_generation.partial_succeeded / partial_failed / completed
+	Model      string          `json:"model"`
+	Created    int64           `json:"created"`
+	ImageIndex int             `json:"image_index"`
+	Url        string          `json:"url,omitempty"`
+	B64Json    string          `json:"b64_json,omitempty"`
+	Size       string          `json:"size,omitempty"`
+	Usage      *VolcImageUsage `json:"usage,omitempty"`
+	Error      *VolcVideoError `json:"error,omitempty"`
+}
+
+// ---- 火山引擎向量化 API 数据结构 ----
+
+// VolcEmbeddingReq 向量化请求（文本/多模态共用）
+type VolcEmbeddingReq struct {
+	Model          string `json:"model"`                     // 模型 ID
+	Input          any    `json:"input"`                     // 文本为字符串数组, 多模态为 EmbeddingInput 数组
+	EncodingFormat string `json:"encoding_format,omitempty"` // float / base64
+	Dimensions     int    `json:"dimensions,omitempty"`      // 输出向量维度
+}
+
+// VolcMultimodalEmbeddingRes 多模态向量化响应, 多个输入融合为一个向量
+type VolcMultimodalEmbeddingRes struct {
+	Id      string `json:"id"`
+	Model   string `json:"model"`
+	Created int64  `json:"created"`
+	Object  string `json:"object"`
+	Data    struct {
+		Object    string `json:"object"`
+		Embedding any    `json:"embedding"` // float 数组或 Base64 编码
+	} `json:"data"`
+	Usage *Usage `json:"usage"` // prompt_tokens_details 包含 text_tokens / image_tokens
+}
+
+// ---- 火山引擎上下文缓存 API 数据结构 ----
+
+// VolcContextCreateReq 创建上下文缓存请求
+type VolcContextCreateReq struct {
+	Model              string                  `json:"model"`                         // 模型 ID
+	Messages           []ChatCompletionMessage `json:"messages"`                      // 需要缓存的消息
+	Mode               string                  `json:"mode"`                          // session / common_prefix
+	Ttl                int64                   `json:"ttl,omitempty"`                 // 过期时长（秒）, 默认 86400
+	TruncationStrategy *VolcTruncationStrategy `json:"truncation_strategy,omitempty"` // session 模式的截断策略
+}
+
+// VolcTruncationStrategy 上下文截断策略
+type VolcTruncationStrategy struct {
+	Type              string `json:"type"`                          // last_history_tokens / rolling_tokens
+	LastHistoryTokens int    `json:"last_history_tokens,omitempty"` // 保留的历史 token 数
+	RollingTokens     *bool  `json:"rolling_tokens,omitempty"`      // 超出时是否自动滚动删除
+}
+
+// VolcContextCreateRes 创建上下文缓存响应
+type VolcContextCreateRes struct {
+	Id                 string                  `json:"id"` // 上下文缓存 ID, 对话时作为 context_id
+	Model              string                  `json:"model"`
+	Mode               string                  `json:"mode"`
+	Ttl                int64                   `json:"ttl"`
+	TruncationStrategy *VolcTruncationStrategy `json:"truncation_strategy,omitempty"`
+	Usage              *Usage                  `json:"usage,omitempty"`
+}
+
+// VolcContextChatReq 上下文缓存对话请求
+type VolcContextChatReq struct {
+	ChatCompletionRequest
+	ContextId string `json:"context_id"` // 上下文缓存 ID
+}
+
+// ---- 火山引擎批量推理 API 数据结构 ----
+
+// VolcTosLocation TOS 存储位置
+type VolcTosLocation struct {
+	BucketName string `json:"BucketName"`
+	ObjectKey  string `json:"ObjectKey"`
+}
+
+// VolcModelReference 批量推理使用的模型
+type VolcModelReference struct {
+	FoundationModel *VolcFoundationModel `json:"FoundationModel,omitempty"` // 基础模型
+	CustomModelId   string               `json:"CustomModelId,omitempty"`   // 精调模型 ID
+}
+
+// VolcFoundationModel 基础模型
+type VolcFoundationModel struct {
+	Name         string `json:"Name"`                   // 模型名称, 如 doubao-1-5-pro-32k
+	ModelVersion string `json:"ModelVersion,omitempty"` // 模型版本, 如 250115
+}
+
+// VolcBatchCreateReq 创建批量推理任务请求
+type VolcBatchCreateReq struct {
+	Name                 string             `json:"Name"`
+	Description          string             `json:"Description,omitempty"`
+	ModelReference       VolcModelReference `json:"ModelReference"`
+	InputFileTosLocation VolcTosLocation    `json:"InputFileTosLocation"` // 输入文件, 格式与 OpenAI 批处理 JSONL 一致
+	OutputDirTosLocation VolcTosLocation    `json:"OutputDirTosLocation"` // 结果输出目录
+	CompletionWindow     string             `json:"CompletionWindow,omitempty"`
+}
+
+// VolcBatchListReq 查询批量推理任务列表请求
+type VolcBatchListReq struct {
+	PageNumber int              `json:"PageNumber,omitempty"`
+	PageSize   int              `json:"PageSize,omitempty"`
+	Filter     *VolcBatchFilter `json:"Filter,omitempty"`
+}
+
+// VolcBatchFilter 批量推理任务过滤条件
+type VolcBatchFilter struct {
+	Ids []string `json:"Ids,omitempty"`
+}
+
+// VolcBatchCancelReq 取消批量推理任务请求
+type VolcBatchCancelReq struct {
+	Id string `json:"Id"`
+}
+
+// VolcBatchJob 批量推理任务
+type VolcBatchJob struct {
+	Id          string `json:"Id"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+	Status      struct {
+		Phase     string `json:"Phase"` // Queued / Running / Completed / Failed / Terminating / Terminated
+		PhaseTime string `json:"PhaseTime"`
+		Message   string `json:"Message"`
+	} `json:"Status"`
+	ModelReference       VolcModelReference `json:"ModelReference"`
+	InputFileTosLocation VolcTosLocation    `json:"InputFileTosLocation"`
+	OutputDirTosLocation VolcTosLocation    `json:"OutputDirTosLocation"`
+	CompletionWindow     string             `json:"CompletionWindow"`
+	RequestCounts        struct {
+		Total     int `json:"Total"`
+		Completed int `json:"Completed"`
+		Failed    int `json:"Failed"`
+	} `json:"RequestCounts"`
+	CreateTime string `json:"CreateTime"` // RFC3339
+	UpdateTime string `json:"UpdateTime"`
+	ExpireTime string `json:"ExpireTime"`
+}
+
+// VolcResponseMetadata OpenAPI 公共响应
+type VolcResponseMetadata struct {
+	RequestId string `json:"RequestId"`
+	Action    string `json:"Action"`
+	Error     *struct {
+		Code    string `json:"Code"`
+		Message string `json:"Message"`
+	} `json:"Error,omitempty"`
+}
+
+// VolcBatchRes 批量推理任务响应（创建/取消共用）
+type VolcBatchRes struct {
+	ResponseMetadata VolcResponseMetadata `json:"ResponseMetadata"`
+	Result           VolcBatchJob         `json:"Result"`
+}
+
+// VolcBatchListRes 批量推理任务列表响应
+type VolcBatchListRes struct {
+	ResponseMetadata VolcResponseMetadata `json:"ResponseMetadata"`
+	Result           struct {
+		TotalCount int            `json:"TotalCount"`
+		PageNumber int            `json:"PageNumber"`
+		PageSize   int            `json:"PageSize"`
+		Items      []VolcBatchJob `json:"Items"`
+	} `json:"Result"`
+}
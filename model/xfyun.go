@@ -78,6 +78,8 @@ type Text struct {
 	Role string `json:"role,omitempty"`
 	// AI的回答内容
 	Content string `json:"content,omitempty"`
+	// 深度思考内容, 仅X1模型返回
+	ReasoningContent string `json:"reasoning_content,omitempty"`
 	// 结果序号，取值为[0,10]; 当前为保留字段，开发者可忽略
 	Index int `json:"index,omitempty"`
 	// 内容类型
@@ -106,3 +108,115 @@ type XfyunUsage struct {
 	// res
 	Text *Text `json:"text,omitempty"`
 }
+
+type XfyunHttpErrorRes struct {
+	Code    int    `json:"code"`    // 错误码, 0表示正常
+	Message string `json:"message"` // 错误描述
+	Sid     string `json:"sid"`     // 会话的唯一id
+}
+
+type XfyunCommon struct {
+	AppId string `json:"app_id"` // 应用appid
+}
+
+type XfyunTtsReq struct {
+	Common   XfyunCommon      `json:"common"`
+	Business XfyunTtsBusiness `json:"business"`
+	Data     XfyunTtsData     `json:"data"`
+}
+
+type XfyunTtsBusiness struct {
+	Aue    string `json:"aue"`           // 音频编码, raw: 未压缩的pcm, lame: mp3
+	Sfl    int    `json:"sfl,omitempty"` // aue=lame时需传1, 开启流式返回
+	Auf    string `json:"auf,omitempty"` // 音频采样率, 如: audio/L16;rate=16000
+	Vcn    string `json:"vcn"`           // 发音人
+	Speed  int    `json:"speed"`         // 语速, 取值范围[0,100], 默认50
+	Volume int    `json:"volume"`        // 音量, 取值范围[0,100], 默认50
+	Pitch  int    `json:"pitch"`         // 音高, 取值范围[0,100], 默认50
+	Tte    string `json:"tte"`           // 文本编码格式
+}
+
+type XfyunTtsData struct {
+	Status int    `json:"status"` // 数据状态, 固定为2
+	Text   string `json:"text"`   // 待合成文本, base64编码
+}
+
+type XfyunTtsRes struct {
+	Code    int    `json:"code"`    // 错误码, 0表示正常
+	Message string `json:"message"` // 错误描述
+	Sid     string `json:"sid"`     // 会话的唯一id
+	Data    *struct {
+		Audio  string `json:"audio"`  // 合成后的音频片段, base64编码
+		Ced    string `json:"ced"`    // 合成进度, 指当前合成文本的字节数
+		Status int    `json:"status"` // 当前音频流状态, 1表示合成中, 2表示合成结束
+	} `json:"data,omitempty"`
+}
+
+type XfyunIatReq struct {
+	Common   *XfyunCommon      `json:"common,omitempty"`   // 仅第一帧需要
+	Business *XfyunIatBusiness `json:"business,omitempty"` // 仅第一帧需要
+	Data     XfyunIatData      `json:"data"`
+}
+
+type XfyunIatBusiness struct {
+	Language string `json:"language"`          // 语种, zh_cn: 中文, en_us: 英文
+	Domain   string `json:"domain"`            // 应用领域, iat: 日常用语
+	Accent   string `json:"accent,omitempty"`  // 方言, mandarin: 中文普通话
+	VadEos   int    `json:"vad_eos,omitempty"` // 静默检测时长, 单位ms, 最大10000
+}
+
+type XfyunIatData struct {
+	Status   int    `json:"status"`   // 音频状态, 0: 第一帧, 1: 中间帧, 2: 最后一帧
+	Format   string `json:"format"`   // 音频采样率, 如: audio/L16;rate=16000
+	Encoding string `json:"encoding"` // 音频编码, raw: 原生pcm, lame: mp3
+	Audio    string `json:"audio"`    // 音频数据, base64编码
+}
+
+type XfyunIatRes struct {
+	Code    int    `json:"code"`    // 错误码, 0表示正常
+	Message string `json:"message"` // 错误描述
+	Sid     string `json:"sid"`     // 会话的唯一id
+	Data    *struct {
+		Status int             `json:"status"` // 识别结果状态, 2表示最后一个结果
+		Result *XfyunIatResult `json:"result"`
+	} `json:"data,omitempty"`
+}
+
+type XfyunIatResult struct {
+	Sn int          `json:"sn"` // 返回结果的序号
+	Ls bool         `json:"ls"` // 是否为最后一片结果
+	Ws []XfyunIatWs `json:"ws"` // 听写结果
+}
+
+type XfyunIatWs struct {
+	Bg int          `json:"bg"` // 起始的端点帧偏移值, 单位: 帧(1帧=10ms)
+	Cw []XfyunIatCw `json:"cw"` // 中文分词
+}
+
+type XfyunIatCw struct {
+	W string `json:"w"` // 字词
+}
+
+type XfyunRtasrRes struct {
+	Action string `json:"action"` // 结果标识, started: 握手, result: 结果, error: 异常
+	Code   string `json:"code"`   // 错误码, 0表示正常
+	Data   string `json:"data"`   // 转写结果, json字符串
+	Desc   string `json:"desc"`   // 错误描述
+	Sid    string `json:"sid"`    // 会话的唯一id
+}
+
+type XfyunRtasrData struct {
+	SegId int `json:"seg_id"` // 转写结果序号
+	Cn    struct {
+		St struct {
+			Bg   string `json:"bg"`   // 句子在整段语音中的开始时间, 单位ms
+			Ed   string `json:"ed"`   // 句子在整段语音中的结束时间, 单位ms
+			Type string `json:"type"` // 结果类型, 0: 最终结果, 1: 中间结果
+			Rt   []struct {
+				Ws []struct {
+					Cw []XfyunIatCw `json:"cw"`
+				} `json:"ws"`
+			} `json:"rt"`
+		} `json:"st"`
+	} `json:"cn"`
+}
@@ -36,6 +36,66 @@ type ZhipuAIChatCompletionReq struct {
 	ToolChoice any `json:"tool_choice,omitempty"`
 	// 终端用户的唯一ID，协助平台对终端用户的违规行为、生成违法及不良信息或其他滥用行为进行干预。ID长度要求：最少6个字符，最多128个字符。
 	UserId string `json:"user_id,omitempty"`
+	// 仅GLM-4.5及以上模型支持，控制是否开启深度思考
+	Thinking *ZhipuAIThinking `json:"thinking,omitempty"`
+}
+
+type ZhipuAIThinking struct {
+	// enabled: 开启思考, disabled: 关闭思考
+	Type string `json:"type"`
+}
+
+type ZhipuAIWebSearchTool struct {
+	// 是否启用搜索, 默认为true
+	Enable bool `json:"enable"`
+	// 搜索引擎, 如: search_std、search_pro
+	SearchEngine string `json:"search_engine,omitempty"`
+	// 是否返回搜索结果
+	SearchResult bool `json:"search_result,omitempty"`
+	// 网页摘要字数, medium: 摘要信息, high: 最大化上下文
+	ContentSize string `json:"content_size,omitempty"`
+}
+
+type ZhipuAIWebSearchResult struct {
+	// 来源网站的图标
+	Icon string `json:"icon"`
+	// 搜索结果的标题
+	Title string `json:"title"`
+	// 搜索结果的网页链接
+	Link string `json:"link"`
+	// 搜索结果网页来源的名称
+	Media string `json:"media"`
+	// 从搜索结果网页中引用的文本内容
+	Content string `json:"content"`
+	// 角标序号, 如: [ref_1]
+	Refer string `json:"refer"`
+	// 网站发布时间
+	PublishDate string `json:"publish_date"`
+}
+
+// 内置工具的调用结果, 在tool_calls中返回
+type ZhipuAIToolCall struct {
+	// 工具类型, 如: retrieval、web_browser
+	Type string `json:"type"`
+	// 知识库检索结果
+	Retrieval *ZhipuAIToolResult `json:"retrieval,omitempty"`
+	// 网页浏览结果
+	WebBrowser *ZhipuAIToolResult `json:"web_browser,omitempty"`
+}
+
+type ZhipuAIToolResult struct {
+	Outputs []ZhipuAIToolOutput `json:"outputs"`
+}
+
+type ZhipuAIToolOutput struct {
+	// 标题, 知识库检索时为文档名称
+	Title string `json:"title"`
+	// 网页链接, 知识库检索时为空
+	Link string `json:"link"`
+	// 引用的文本内容
+	Content string `json:"content"`
+	// 角标序号, 如: [ref_1]
+	Refer string `json:"refer"`
 }
 
 type ZhipuAIChatCompletionRes struct {
@@ -49,6 +109,8 @@ type ZhipuAIChatCompletionRes struct {
 	Choices []ChatCompletionChoice `json:"choices"`
 	// 结束时返回本次模型调用的 tokens 数量统计。
 	Usage *Usage `json:"usage"`
+	// 联网搜索结果, 使用web_search工具且search_result为true时返回
+	WebSearch []ZhipuAIWebSearchResult `json:"web_search,omitempty"`
 	// 当failed时会有错误信息
 	Error ZhipuAIError `json:"error"`
 }
@@ -61,3 +123,110 @@ type ZhipuAIError struct {
 type ZhipuAIErrorResponse struct {
 	Error *ZhipuAIError `json:"error,omitempty"`
 }
+
+type ZhipuAIImageReq struct {
+	// 模型编码
+	Model string `json:"model"`
+	// 所需图像的文本描述
+	Prompt string `json:"prompt"`
+	// 生成图像的质量, hd: 生成更精细、细节更丰富的图像, standard: 快速生成图像, 默认为standard
+	Quality string `json:"quality,omitempty"`
+	// 图片尺寸, 如: 1024x1024
+	Size string `json:"size,omitempty"`
+	// 终端用户的唯一ID
+	UserId string `json:"user_id,omitempty"`
+}
+
+type ZhipuAIImageRes struct {
+	// 请求创建时间, 是以秒为单位的Unix时间戳
+	Created int64 `json:"created"`
+	// 图片链接, 临时链接有效期为30天
+	Data []struct {
+		Url string `json:"url"`
+	} `json:"data"`
+	// 当failed时会有错误信息
+	Error *ZhipuAIError `json:"error,omitempty"`
+}
+
+type ZhipuAIVideoReq struct {
+	// 模型编码
+	Model string `json:"model"`
+	// 视频的文本描述
+	Prompt string `json:"prompt,omitempty"`
+	// 首帧图片, 支持URL或Base64编码
+	ImageUrl string `json:"image_url,omitempty"`
+	// 输出模式, quality: 质量优先, speed: 速度优先
+	Quality string `json:"quality,omitempty"`
+	// 是否生成AI音效
+	WithAudio bool `json:"with_audio,omitempty"`
+	// 视频分辨率, 如: 1920x1080
+	Size string `json:"size,omitempty"`
+	// 视频帧率, 可选值为30或60
+	Fps int `json:"fps,omitempty"`
+	// 视频时长, 可选值为5或10, 单位秒
+	Duration int `json:"duration,omitempty"`
+	// 终端用户的唯一ID
+	UserId string `json:"user_id,omitempty"`
+}
+
+type ZhipuAIAsyncRes struct {
+	// 任务ID, 用于查询异步结果
+	Id string `json:"id"`
+	// 模型名称
+	Model string `json:"model"`
+	// 请求ID
+	RequestId string `json:"request_id"`
+	// 处理状态, PROCESSING: 处理中, SUCCESS: 成功, FAIL: 失败
+	TaskStatus string `json:"task_status"`
+	// 视频生成结果
+	VideoResult []struct {
+		Url           string `json:"url"`
+		CoverImageUrl string `json:"cover_image_url"`
+	} `json:"video_result,omitempty"`
+	// 当failed时会有错误信息
+	Error *ZhipuAIError `json:"error,omitempty"`
+}
+
+type ZhipuAIEmbeddingReq struct {
+	// 模型编码, 如: embedding-2、embedding-3
+	Model string `json:"model"`
+	// 需要向量化的文本, 支持字符串或字符串数组
+	Input any `json:"input"`
+	// 输出向量维度, 仅embedding-3支持, 可选值为256、512、1024、2048, 默认为2048
+	Dimensions int `json:"dimensions,omitempty"`
+}
+
+type ZhipuAIEmbeddingRes struct {
+	// 模型名称
+	Model string `json:"model"`
+	// 结果类型, 目前为list
+	Object string `json:"object"`
+	// 向量化结果
+	Data []struct {
+		Index     int       `json:"index"`
+		Object    string    `json:"object"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+	// tokens数量统计
+	Usage *Usage `json:"usage"`
+	// 当failed时会有错误信息
+	Error *ZhipuAIError `json:"error,omitempty"`
+}
+
+type ZhipuAIBatchCreateReq struct {
+	// 上传的批处理输入文件ID
+	InputFileId string `json:"input_file_id"`
+	// 批处理请求的接口路径, 如: /v4/chat/completions、/v4/embeddings
+	Endpoint string `json:"endpoint"`
+	// 处理时限, 目前仅支持24h
+	CompletionWindow string `json:"completion_window,omitempty"`
+	// 自定义元数据
+	Metadata any `json:"metadata,omitempty"`
+}
+
+type ZhipuAIBatchInput struct {
+	CustomId string `json:"custom_id"`
+	Method   string `json:"method"`
+	Url      string `json:"url"`
+	Body     any    `json:"body"`
+}
@@ -62,7 +62,10 @@ func (o *OpenAI) ConvChatCompletionsRequest(ctx context.Context, data any) (requ
 	}
 
 	if o.IsSupportSystemRole != nil {
-		request.Messages = common.HandleMessages(request.Messages, *o.IsSupportSystemRole)
+		request.Messages = common.HandleMessagesWithOptions(request.Messages, common.MessagesOptions{
+			IsSupportSystemRole:    *o.IsSupportSystemRole,
+			IsSupportDeveloperRole: true,
+		})
 	}
 
 	return request, nil
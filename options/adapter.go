@@ -19,4 +19,7 @@ type AdapterOptions struct {
 	ResPassthroughParams []string          // 响应透传参数
 	PassthroughHeader    map[string]string // 透传请求头
 	Async                bool              // 异步
+	Region               string            // 管控面OpenAPI所在地域, 如火山引擎批量推理
+	AccessKey            string            // 管控面OpenAPI签名使用的AccessKey, 与Key分开配置
+	SecretKey            string            // 管控面OpenAPI签名使用的SecretKey
 }
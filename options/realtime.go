@@ -0,0 +1,24 @@
+package options
+
+import (
+	"context"
+	"time"
+
+	"github.com/iimeta/fastapi-sdk/v2/model"
+)
+
+type RealtimeOptions struct {
+	Provider          string
+	Model             string
+	Key               string
+	BaseUrl           string
+	Path              string
+	ProxyUrl          string
+	PassthroughHeader map[string]string                                               // 透传请求头
+	IsConvEvent       bool                                                            // 是否将服务商消息与OpenAI Realtime事件互相转换
+	PingInterval      time.Duration                                                   // 心跳间隔, 为0时使用默认值
+	ReadTimeout       time.Duration                                                   // 读超时时间, 收到消息或pong时重新计时, 为0时不超时
+	WriteTimeout      time.Duration                                                   // 写超时时间, 为0时使用默认值
+	MaxReconnects     int                                                             // 异常断开后的最大重连次数, 为0时不重连
+	OnReconnect       func(ctx context.Context, attempt int) []*model.RealtimeRequest // 重连成功后发送的会话恢复消息
+}
@@ -1,95 +1,214 @@
 package sdk
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gogf/gf/v2/os/grpool"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gorilla/websocket"
+	"github.com/iimeta/fastapi-sdk/v2/consts"
 	"github.com/iimeta/fastapi-sdk/v2/errors"
+	"github.com/iimeta/fastapi-sdk/v2/google"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/options"
 	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
+// RealtimeConverter 服务商实时消息与OpenAI Realtime事件的转换
+type RealtimeConverter interface {
+	ConvRealtimeSessionCreated(ctx context.Context) []byte
+	ConvRealtimeRequest(ctx context.Context, message []byte) (messages [][]byte, err error)
+	ConvRealtimeResponse(ctx context.Context, message []byte) (events [][]byte, usage *model.Usage, err error)
+	ConvRealtimeReset(ctx context.Context)
+}
+
 type RealtimeClient struct {
-	model    string
-	key      string
-	baseURL  string
-	path     string
-	proxyURL string
+	provider          string
+	model             string
+	key               string
+	baseURL           string
+	path              string
+	proxyURL          string
+	apiVersion        string
+	passthroughHeader map[string]string
+	converter         RealtimeConverter
+	pingInterval      time.Duration
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	maxReconnects     int
+	onReconnect       func(ctx context.Context, attempt int) []*model.RealtimeRequest
 }
 
 func NewRealtimeClient(ctx context.Context, model, key, baseURL, path string, proxyURL ...string) *RealtimeClient {
 
-	logger.Infof(ctx, "NewRealtimeClient OpenAI model: %s, key: %s", model, key)
+	realtimeOptions := &options.RealtimeOptions{
+		Provider: consts.PROVIDER_OPENAI,
+		Model:    model,
+		Key:      key,
+		BaseUrl:  baseURL,
+		Path:     path,
+	}
+
+	if len(proxyURL) > 0 {
+		realtimeOptions.ProxyUrl = proxyURL[0]
+	}
+
+	return NewRealtimeClientWithOptions(ctx, realtimeOptions)
+}
+
+func NewRealtimeClientWithOptions(ctx context.Context, realtimeOptions *options.RealtimeOptions) *RealtimeClient {
+
+	if realtimeOptions.Provider == "" {
+		realtimeOptions.Provider = consts.PROVIDER_OPENAI
+	}
+
+	logger.Infof(ctx, "NewRealtimeClient %s model: %s, key: %s", realtimeOptions.Provider, realtimeOptions.Model, realtimeOptions.Key)
 
 	realtimeClient := &RealtimeClient{
-		model:   model,
-		key:     key,
-		baseURL: "wss://api.openai.com/v1",
-		path:    "/realtime",
+		provider:          realtimeOptions.Provider,
+		model:             realtimeOptions.Model,
+		key:               realtimeOptions.Key,
+		passthroughHeader: realtimeOptions.PassthroughHeader,
+		pingInterval:      realtimeOptions.PingInterval,
+		readTimeout:       realtimeOptions.ReadTimeout,
+		writeTimeout:      realtimeOptions.WriteTimeout,
+		maxReconnects:     realtimeOptions.MaxReconnects,
+		onReconnect:       realtimeOptions.OnReconnect,
 	}
 
-	if baseURL != "" {
-		logger.Infof(ctx, "NewRealtimeClient OpenAI model: %s, baseUrl: %s", model, baseURL)
-		realtimeClient.baseURL = baseURL
+	switch realtimeOptions.Provider {
+	case consts.PROVIDER_GOOGLE:
+
+		realtimeClient.baseURL = "wss://generativelanguage.googleapis.com"
+		realtimeClient.path = "/ws/google.ai.generativelanguage.v1beta.GenerativeService.BidiGenerateContent"
+
+		if realtimeOptions.IsConvEvent {
+			realtimeClient.converter = google.NewAdapter(ctx, &options.AdapterOptions{
+				Provider: realtimeOptions.Provider,
+				Model:    realtimeOptions.Model,
+				Key:      realtimeOptions.Key,
+			})
+		}
+
+	case consts.PROVIDER_AZURE:
+		realtimeClient.path = "/openai/realtime"
+		realtimeClient.apiVersion = "2024-10-01-preview"
+	default:
+		realtimeClient.baseURL = "wss://api.openai.com/v1"
+		realtimeClient.path = "/realtime"
 	}
 
-	if path != "" {
-		logger.Infof(ctx, "NewRealtimeClient OpenAI model: %s, path: %s", model, path)
-		realtimeClient.path = path
+	if realtimeOptions.BaseUrl != "" {
+
+		logger.Infof(ctx, "NewRealtimeClient %s model: %s, baseUrl: %s", realtimeOptions.Provider, realtimeOptions.Model, realtimeOptions.BaseUrl)
+		realtimeClient.baseURL = strings.TrimSuffix(realtimeOptions.BaseUrl, "/")
+
+		if realtimeOptions.Provider == consts.PROVIDER_AZURE && strings.HasSuffix(realtimeClient.baseURL, "/openai") {
+			realtimeClient.path = "/realtime"
+		}
 	}
 
-	if len(proxyURL) > 0 && proxyURL[0] != "" {
-		logger.Infof(ctx, "NewRealtimeClient OpenAI model: %s, proxyURL: %s", model, proxyURL[0])
-		realtimeClient.proxyURL = proxyURL[0]
+	if realtimeOptions.Path != "" {
+
+		logger.Infof(ctx, "NewRealtimeClient %s model: %s, path: %s", realtimeOptions.Provider, realtimeOptions.Model, realtimeOptions.Path)
+		realtimeClient.path = realtimeOptions.Path
+
+		// Azure可在路径中指定api-version
+		if realtimeOptions.Provider == consts.PROVIDER_AZURE {
+
+			split := gstr.Split(realtimeClient.path, "?api-version=")
+			if len(split) != 2 {
+				split = gstr.Split(realtimeClient.path, "api-version=")
+			}
+
+			realtimeClient.path = split[0]
+
+			if len(split) > 1 && split[1] != "" {
+				realtimeClient.apiVersion = split[1]
+			}
+		}
+	}
+
+	if realtimeOptions.ProxyUrl != "" {
+		logger.Infof(ctx, "NewRealtimeClient %s model: %s, proxyURL: %s", realtimeOptions.Provider, realtimeOptions.Model, realtimeOptions.ProxyUrl)
+		realtimeClient.proxyURL = realtimeOptions.ProxyUrl
 	}
 
 	return realtimeClient
 }
 
+// Realtime 向requestChan发送nil或MessageType为-1的请求结束会话, 收到nil响应表示连接已关闭;
+// 收到CloseMessage响应时会话已结束, 写协程随之退出, 调用方不应再向requestChan发送消息
 func (c *RealtimeClient) Realtime(ctx context.Context, requestChan chan *model.RealtimeRequest) (responseChan chan *model.RealtimeResponse, err error) {
 
-	logger.Infof(ctx, "Realtime OpenAI model: %s start", c.model)
+	logger.Infof(ctx, "Realtime %s model: %s start", c.provider, c.model)
 
 	now := gtime.TimestampMilli()
 	defer func() {
-		logger.Infof(ctx, "Realtime OpenAI model: %s totalTime: %d ms", c.model, gtime.TimestampMilli()-now)
+		logger.Infof(ctx, "Realtime %s model: %s totalTime: %d ms", c.provider, c.model, gtime.TimestampMilli()-now)
 	}()
 
-	requestHeader := http.Header{
-		"Authorization": {"Bearer " + c.key},
-		"OpenAI-Beta":   {"realtime=v1"},
+	// Azure资源地址因资源而异, 无法提供默认值
+	if c.provider == consts.PROVIDER_AZURE && c.baseURL == "" {
+		err = errors.New("Azure realtime requires baseURL, e.g. wss://{resource}.openai.azure.com")
+		logger.Errorf(ctx, "Realtime %s model: %s, error: %v", c.provider, c.model, err)
+		return
 	}
 
-	conn, err := util.WebSocketClient(ctx, c.getWebSocketUrl(ctx), requestHeader, 0, nil, c.proxyURL)
+	conn, err := c.dial(ctx)
 	if err != nil {
-		logger.Errorf(ctx, "Realtime OpenAI model: %s, error: %v", c.model, err)
+		logger.Errorf(ctx, "Realtime %s model: %s, error: %v", c.provider, c.model, err)
 		return
 	}
 
-	duration := gtime.TimestampMilli()
+	var (
+		duration    = gtime.TimestampMilli()
+		closed      atomic.Bool              // 调用方是否已结束会话
+		readDone    = make(chan struct{})    // 读协程退出, 会话结束
+		reconnected = make(chan struct{}, 1) // 重连成功, 写协程重试发送失败的消息
+		connMutex   sync.RWMutex
+		getConn     = func() *util.WebSocketConn {
+			connMutex.RLock()
+			defer connMutex.RUnlock()
+			return conn
+		}
+	)
+
 	responseChan = make(chan *model.RealtimeResponse)
 
 	// WriteMessage
 	if err := grpool.AddWithRecover(ctx, func(ctx context.Context) {
 
 		defer func() {
-			logger.Infof(ctx, "Realtime OpenAI WriteMessage model: %s totalTime: %d ms", c.model, gtime.TimestampMilli()-now)
+			logger.Infof(ctx, "Realtime %s WriteMessage model: %s totalTime: %d ms", c.provider, c.model, gtime.TimestampMilli()-now)
 		}()
 
 		for {
 
-			request := <-requestChan
+			var request *model.RealtimeRequest
+
+			select {
+			case request = <-requestChan:
+			case <-readDone:
+				// 读协程已向调用方返回关闭或错误响应, 不再发送消息
+				return
+			}
 
 			if request == nil || request.MessageType == -1 {
 
-				if err := conn.Close(); err != nil {
-					logger.Errorf(ctx, "Realtime OpenAI WriteMessage model: %s, conn.Close error: %v", c.model, err)
+				closed.Store(true)
+
+				if err := getConn().Close(); err != nil {
+					logger.Errorf(ctx, "Realtime %s WriteMessage model: %s, conn.Close error: %v", c.provider, c.model, err)
 				}
 
 				responseChan <- nil
@@ -97,42 +216,119 @@ func (c *RealtimeClient) Realtime(ctx context.Context, requestChan chan *model.R
 				return
 			}
 
-			if err := conn.WriteMessage(ctx, request.MessageType, request.Message); err != nil {
-				logger.Errorf(ctx, "Realtime OpenAI WriteMessage model: %s, error: %v", c.model, err)
-				return
+			for {
+
+				err := c.writeRequest(ctx, getConn(), request)
+				if err == nil {
+					break
+				}
+
+				logger.Errorf(ctx, "Realtime %s WriteMessage model: %s, error: %v", c.provider, c.model, err)
+
+				if c.maxReconnects == 0 {
+					return
+				}
+
+				// 等待读协程重连成功后在新连接上重发, 重连失败时读协程已向调用方返回错误
+				select {
+				case <-reconnected:
+				case <-readDone:
+					logger.Errorf(ctx, "Realtime %s WriteMessage model: %s, reconnect failed, message dropped", c.provider, c.model)
+					return
+				}
 			}
 		}
 
 	}, nil); err != nil {
-		logger.Errorf(ctx, "Realtime OpenAI WriteMessage model: %s, error: %v", c.model, err)
+		logger.Errorf(ctx, "Realtime %s WriteMessage model: %s, error: %v", c.provider, c.model, err)
 		return nil, err
 	}
 
 	// ReadMessage
 	if err = grpool.AddWithRecover(ctx, func(ctx context.Context) {
 
+		defer close(readDone)
+
 		defer func() {
 			end := gtime.TimestampMilli()
-			logger.Infof(ctx, "Realtime OpenAI ReadMessage model: %s connTime: %d ms, duration: %d ms, totalTime: %d ms", c.model, duration-now, end-duration, end-now)
+			logger.Infof(ctx, "Realtime %s ReadMessage model: %s connTime: %d ms, duration: %d ms, totalTime: %d ms", c.provider, c.model, duration-now, end-duration, end-now)
 		}()
 
+		if c.converter != nil {
+			responseChan <- &model.RealtimeResponse{
+				MessageType: websocket.TextMessage,
+				Message:     c.converter.ConvRealtimeSessionCreated(ctx),
+				ConnTime:    duration - now,
+			}
+		}
+
 		for {
 
-			messageType, message, err := conn.ReadMessage(ctx)
-			if err != nil && !errors.Is(err, io.EOF) {
+			messageType, message, err := getConn().ReadMessage(ctx)
+			if err != nil {
 
-				if !errors.Is(err, context.Canceled) {
-					logger.Errorf(ctx, "Realtime OpenAI ReadMessage model: %s, error: %v", c.model, err)
+				// 本端主动关闭, 写协程已通知调用方
+				if errors.Is(err, util.ErrWebSocketClosed) || closed.Load() {
+					return
 				}
 
-				end := gtime.TimestampMilli()
-				responseChan <- &model.RealtimeResponse{
-					ConnTime:  duration - now,
-					Duration:  end - duration,
-					TotalTime: end - now,
-					Error:     err,
+				if !util.IsNormalClose(err) && !errors.Is(err, context.Canceled) && c.maxReconnects > 0 {
+
+					logger.Infof(ctx, "Realtime %s ReadMessage model: %s, error: %v, reconnecting", c.provider, c.model, err)
+
+					newConn, reconnectErr := c.reconnectWithRetry(ctx)
+					if reconnectErr == nil {
+
+						connMutex.Lock()
+						conn = newConn
+						connMutex.Unlock()
+
+						select {
+						case reconnected <- struct{}{}:
+						default:
+						}
+
+						// 重连期间调用方已结束会话, 关闭新连接
+						if closed.Load() {
+							if err := newConn.Close(); err != nil {
+								logger.Error(ctx, err)
+							}
+							return
+						}
+
+						continue
+					}
+
+					// 重连次数用尽, 向调用方返回最后一次重连错误
+					err = reconnectErr
+				}
+
+				closeCode := util.CloseCode(err)
+
+				response := &model.RealtimeResponse{
+					MessageType: websocket.CloseMessage,
+					CloseCode:   closeCode,
+					ConnTime:    duration - now,
+				}
+
+				if util.IsNormalClose(err) {
+					logger.Infof(ctx, "Realtime %s ReadMessage model: %s, connection closed, code: %d", c.provider, c.model, closeCode)
+					response.Message = websocket.FormatCloseMessage(closeCode, "")
+				} else {
+
+					if !errors.Is(err, context.Canceled) {
+						logger.Errorf(ctx, "Realtime %s ReadMessage model: %s, error: %v", c.provider, c.model, err)
+					}
+
+					response.Error = err
 				}
 
+				end := gtime.TimestampMilli()
+				response.Duration = end - duration
+				response.TotalTime = end - now
+
+				responseChan <- response
+
 				return
 			}
 
@@ -140,29 +336,243 @@ func (c *RealtimeClient) Realtime(ctx context.Context, requestChan chan *model.R
 				return
 			}
 
-			response := &model.RealtimeResponse{
-				MessageType: messageType,
-				Message:     message,
-				ConnTime:    duration - now,
+			if c.converter == nil {
+
+				response := &model.RealtimeResponse{
+					MessageType: messageType,
+					Message:     message,
+					ConnTime:    duration - now,
+				}
+
+				if messageType == websocket.TextMessage {
+					response.Usage = c.getUsage(ctx, message)
+				}
+
+				end := gtime.TimestampMilli()
+				response.Duration = end - duration
+				response.TotalTime = end - now
+
+				responseChan <- response
+
+				continue
 			}
 
-			end := gtime.TimestampMilli()
-			response.Duration = end - duration
-			response.TotalTime = end - now
+			events, usage, err := c.converter.ConvRealtimeResponse(ctx, message)
+			if err != nil {
+				logger.Errorf(ctx, "Realtime %s ReadMessage model: %s, ConvRealtimeResponse error: %v", c.provider, c.model, err)
+				continue
+			}
+
+			for i, event := range events {
+
+				response := &model.RealtimeResponse{
+					MessageType: websocket.TextMessage,
+					Message:     event,
+					ConnTime:    duration - now,
+				}
 
-			responseChan <- response
+				// 用量随本轮最后一个事件(response.done)返回
+				if i == len(events)-1 {
+					response.Usage = usage
+				}
+
+				end := gtime.TimestampMilli()
+				response.Duration = end - duration
+				response.TotalTime = end - now
+
+				responseChan <- response
+			}
 		}
 
 	}, nil); err != nil {
-		logger.Errorf(ctx, "Realtime OpenAI ReadMessage model: %s, error: %v", c.model, err)
+		logger.Errorf(ctx, "Realtime %s ReadMessage model: %s, error: %v", c.provider, c.model, err)
 		return
 	}
 
 	return responseChan, nil
 }
 
+func (c *RealtimeClient) dial(ctx context.Context) (*util.WebSocketConn, error) {
+
+	webSocketOptions := util.DefaultWebSocketOptions
+
+	if c.pingInterval != 0 {
+		webSocketOptions.PingInterval = c.pingInterval
+	}
+
+	if c.readTimeout != 0 {
+		webSocketOptions.ReadTimeout = c.readTimeout
+	}
+
+	if c.writeTimeout != 0 {
+		webSocketOptions.WriteTimeout = c.writeTimeout
+	}
+
+	return util.WebSocketClientWithOptions(ctx, c.getWebSocketUrl(ctx), c.getRequestHeader(), 0, nil, c.proxyURL, webSocketOptions)
+}
+
+// 依次重试直至成功或达到最大重连次数
+func (c *RealtimeClient) reconnectWithRetry(ctx context.Context) (conn *util.WebSocketConn, err error) {
+
+	for attempt := 1; attempt <= c.maxReconnects; attempt++ {
+
+		if conn, err = c.reconnect(ctx, attempt); err == nil {
+			return conn, nil
+		}
+
+		logger.Errorf(ctx, "Realtime %s model: %s, reconnect attempt: %d, error: %v", c.provider, c.model, attempt, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, err
+}
+
+// 异常断开后重新建立连接, 并发送调用方提供的会话恢复消息
+func (c *RealtimeClient) reconnect(ctx context.Context, attempt int) (*util.WebSocketConn, error) {
+
+	timer := time.NewTimer(time.Duration(attempt) * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.converter != nil {
+		c.converter.ConvRealtimeReset(ctx)
+	}
+
+	if c.onReconnect != nil {
+		for _, request := range c.onReconnect(ctx, attempt) {
+			if err = c.writeRequest(ctx, conn, request); err != nil {
+
+				if err := conn.Close(); err != nil {
+					logger.Error(ctx, err)
+				}
+
+				return nil, err
+			}
+		}
+	}
+
+	return conn, nil
+}
+
+func (c *RealtimeClient) writeRequest(ctx context.Context, conn *util.WebSocketConn, request *model.RealtimeRequest) error {
+
+	if c.converter == nil || request.MessageType != websocket.TextMessage {
+		return conn.WriteMessage(ctx, request.MessageType, request.Message)
+	}
+
+	messages, err := c.converter.ConvRealtimeRequest(ctx, request.Message)
+	if err != nil {
+		// 无法转换的事件直接忽略, 不中断会话
+		logger.Errorf(ctx, "Realtime %s model: %s, ConvRealtimeRequest error: %v", c.provider, c.model, err)
+		return nil
+	}
+
+	for _, message := range messages {
+		if err = conn.WriteMessage(ctx, websocket.TextMessage, message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *RealtimeClient) getRequestHeader() http.Header {
+
+	requestHeader := http.Header{}
+
+	switch c.provider {
+	case consts.PROVIDER_GOOGLE:
+		requestHeader.Set("x-goog-api-key", c.key)
+	case consts.PROVIDER_AZURE:
+		requestHeader.Set("api-key", c.key)
+	default:
+		requestHeader.Set("Authorization", "Bearer "+c.key)
+		requestHeader.Set("OpenAI-Beta", "realtime=v1")
+	}
+
+	for k, v := range c.passthroughHeader {
+		requestHeader.Set(k, v)
+	}
+
+	return requestHeader
+}
+
 func (c *RealtimeClient) getWebSocketUrl(ctx context.Context) string {
-	webSocketUrl := gstr.Replace(gstr.Replace(fmt.Sprintf("%s%s?model=%s", c.baseURL, c.path, c.model), "https://", "wss://"), "http://", "ws://")
-	logger.Infof(ctx, "Realtime OpenAI model: %s, webSocketUrl: %s", c.model, webSocketUrl)
+
+	var webSocketUrl string
+
+	switch c.provider {
+	case consts.PROVIDER_GOOGLE:
+		// 模型在setup消息中指定
+		webSocketUrl = c.baseURL + c.path
+	case consts.PROVIDER_AZURE:
+		webSocketUrl = fmt.Sprintf("%s%s?api-version=%s&deployment=%s", c.baseURL, c.path, c.apiVersion, c.model)
+	default:
+		webSocketUrl = fmt.Sprintf("%s%s?model=%s", c.baseURL, c.path, c.model)
+	}
+
+	webSocketUrl = gstr.Replace(gstr.Replace(webSocketUrl, "https://", "wss://"), "http://", "ws://")
+	logger.Infof(ctx, "Realtime %s model: %s, webSocketUrl: %s", c.provider, c.model, webSocketUrl)
+
 	return webSocketUrl
 }
+
+// 从response.done事件中提取用量, 其它事件返回nil
+func (c *RealtimeClient) getUsage(ctx context.Context, message []byte) *model.Usage {
+
+	if !bytes.Contains(message, []byte(consts.REALTIME_EVENT_RESPONSE_DONE)) {
+		return nil
+	}
+
+	event, err := ParseRealtimeServerEvent(message)
+	if err != nil {
+		logger.Errorf(ctx, "Realtime %s model: %s, ParseRealtimeServerEvent error: %v", c.provider, c.model, err)
+		return nil
+	}
+
+	if event.Type != consts.REALTIME_EVENT_RESPONSE_DONE || event.Response == nil || event.Response.Usage == nil {
+		return nil
+	}
+
+	realtimeUsage := event.Response.Usage
+
+	usage := &model.Usage{
+		PromptTokens:     realtimeUsage.InputTokens,
+		CompletionTokens: realtimeUsage.OutputTokens,
+		TotalTokens:      realtimeUsage.TotalTokens,
+		InputTokens:      realtimeUsage.InputTokens,
+		OutputTokens:     realtimeUsage.OutputTokens,
+		PromptTokensDetails: model.PromptTokensDetails{
+			TextTokens:   realtimeUsage.InputTokenDetails.TextTokens,
+			AudioTokens:  realtimeUsage.InputTokenDetails.AudioTokens,
+			CachedTokens: realtimeUsage.InputTokenDetails.CachedTokens,
+		},
+		CompletionTokensDetails: model.CompletionTokensDetails{
+			TextTokens:  realtimeUsage.OutputTokenDetails.TextTokens,
+			AudioTokens: realtimeUsage.OutputTokenDetails.AudioTokens,
+		},
+		InputTokensDetails: model.InputTokensDetails{
+			TextTokens:   realtimeUsage.InputTokenDetails.TextTokens,
+			AudioTokens:  realtimeUsage.InputTokenDetails.AudioTokens,
+			CachedTokens: realtimeUsage.InputTokenDetails.CachedTokens,
+		},
+		OutputTokensDetails: model.OutputTokensDetails{
+			TextTokens: realtimeUsage.OutputTokenDetails.TextTokens,
+		},
+	}
+
+	return usage
+}
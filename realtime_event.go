@@ -0,0 +1,108 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gorilla/websocket"
+	"github.com/iimeta/fastapi-sdk/v2/consts"
+	"github.com/iimeta/fastapi-sdk/v2/model"
+)
+
+// NewRealtimeRequest 将客户端事件编码为文本消息
+func NewRealtimeRequest(event any) *model.RealtimeRequest {
+	return &model.RealtimeRequest{
+		MessageType: websocket.TextMessage,
+		Message:     gjson.MustEncode(event),
+	}
+}
+
+func RealtimeSessionUpdate(session model.RealtimeSession) *model.RealtimeRequest {
+	return NewRealtimeRequest(model.RealtimeClientEvent{
+		Type:    consts.REALTIME_EVENT_SESSION_UPDATE,
+		Session: &session,
+	})
+}
+
+// RealtimeInputAudioBufferAppend audio为原始音频数据, 按协议进行base64编码
+func RealtimeInputAudioBufferAppend(audio []byte) *model.RealtimeRequest {
+	return NewRealtimeRequest(model.RealtimeClientEvent{
+		Type:  consts.REALTIME_EVENT_INPUT_AUDIO_BUFFER_APPEND,
+		Audio: base64.StdEncoding.EncodeToString(audio),
+	})
+}
+
+func RealtimeInputAudioBufferCommit() *model.RealtimeRequest {
+	return NewRealtimeRequest(model.RealtimeClientEvent{
+		Type: consts.REALTIME_EVENT_INPUT_AUDIO_BUFFER_COMMIT,
+	})
+}
+
+func RealtimeInputAudioBufferClear() *model.RealtimeRequest {
+	return NewRealtimeRequest(model.RealtimeClientEvent{
+		Type: consts.REALTIME_EVENT_INPUT_AUDIO_BUFFER_CLEAR,
+	})
+}
+
+func RealtimeConversationItemCreate(item model.RealtimeConversationItem, previousItemId ...string) *model.RealtimeRequest {
+
+	event := model.RealtimeClientEvent{
+		Type: consts.REALTIME_EVENT_CONVERSATION_ITEM_CREATE,
+		Item: &item,
+	}
+
+	if len(previousItemId) > 0 {
+		event.PreviousItemId = previousItemId[0]
+	}
+
+	return NewRealtimeRequest(event)
+}
+
+func RealtimeConversationItemTruncate(itemId string, contentIndex, audioEndMs int) *model.RealtimeRequest {
+	return NewRealtimeRequest(model.RealtimeClientEvent{
+		Type:         consts.REALTIME_EVENT_CONVERSATION_ITEM_TRUNCATE,
+		ItemId:       itemId,
+		ContentIndex: &contentIndex,
+		AudioEndMs:   &audioEndMs,
+	})
+}
+
+func RealtimeConversationItemDelete(itemId string) *model.RealtimeRequest {
+	return NewRealtimeRequest(model.RealtimeClientEvent{
+		Type:   consts.REALTIME_EVENT_CONVERSATION_ITEM_DELETE,
+		ItemId: itemId,
+	})
+}
+
+// RealtimeResponseCreate response为空时使用会话配置
+func RealtimeResponseCreate(response *model.RealtimeResponseConfig) *model.RealtimeRequest {
+	return NewRealtimeRequest(model.RealtimeClientEvent{
+		Type:     consts.REALTIME_EVENT_RESPONSE_CREATE,
+		Response: response,
+	})
+}
+
+func RealtimeResponseCancel(responseId ...string) *model.RealtimeRequest {
+
+	event := model.RealtimeClientEvent{
+		Type: consts.REALTIME_EVENT_RESPONSE_CANCEL,
+	}
+
+	if len(responseId) > 0 {
+		event.ResponseId = responseId[0]
+	}
+
+	return NewRealtimeRequest(event)
+}
+
+// ParseRealtimeServerEvent 解析服务端事件
+func ParseRealtimeServerEvent(message []byte) (*model.RealtimeServerEvent, error) {
+
+	event := new(model.RealtimeServerEvent)
+	if err := json.Unmarshal(message, event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
@@ -2,27 +2,58 @@ package util
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogf/gf/v2/net/gclient"
+	"github.com/gogf/gf/v2/os/grpool"
 	"github.com/gorilla/websocket"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 )
 
+// ErrWebSocketClosed 连接已被本端关闭
+var ErrWebSocketClosed = errors.New("websocket: connection closed")
+
+type WebSocketOptions struct {
+	HandshakeTimeout time.Duration // 握手超时时间
+	PingInterval     time.Duration // 心跳间隔, 为0时不发送ping
+	ReadTimeout      time.Duration // 读超时时间, 收到消息或pong时重新计时, 为0时不超时
+	WriteTimeout     time.Duration // 写超时时间, 为0时不超时
+}
+
+// DefaultWebSocketOptions 默认每30秒发送一次心跳, 读超时需由调用方按需开启
+var DefaultWebSocketOptions = WebSocketOptions{
+	HandshakeTimeout: 60 * time.Second,
+	PingInterval:     30 * time.Second,
+	WriteTimeout:     10 * time.Second,
+}
+
 type WebSocketConn struct {
-	conn     *websocket.Conn
-	response *http.Response
+	conn       *websocket.Conn
+	response   *http.Response
+	options    WebSocketOptions
+	writeMutex sync.Mutex
+	closeOnce  sync.Once
+	closed     chan struct{}
+	isClosed   atomic.Bool // 是否由本端调用Close关闭, 读错误导致的关闭不计入
 }
 
 func WebSocketClient(ctx context.Context, wsURL string, requestHeader http.Header, messageType int, message []byte, proxyURL string) (*WebSocketConn, error) {
+	return WebSocketClientWithOptions(ctx, wsURL, requestHeader, messageType, message, proxyURL, DefaultWebSocketOptions)
+}
+
+func WebSocketClientWithOptions(ctx context.Context, wsURL string, requestHeader http.Header, messageType int, message []byte, proxyURL string, options WebSocketOptions) (*WebSocketConn, error) {
 
 	logger.Infof(ctx, "WebSocketClient wsURL: %s", wsURL)
 
 	client := gclient.NewWebSocket()
 
-	client.HandshakeTimeout = 60 * time.Second // 设置超时时间
+	client.HandshakeTimeout = options.HandshakeTimeout // 设置超时时间
 	//client.TLSClientConfig = &tls.Config{}   // 设置 tls 配置
 
 	// 设置代理
@@ -34,7 +65,7 @@ func WebSocketClient(ctx context.Context, wsURL string, requestHeader http.Heade
 		}
 	}
 
-	conn, response, err := client.Dial(wsURL, requestHeader)
+	conn, response, err := client.DialContext(ctx, wsURL, requestHeader)
 	if err != nil {
 		logger.Error(ctx, err)
 
@@ -53,15 +84,25 @@ func WebSocketClient(ctx context.Context, wsURL string, requestHeader http.Heade
 		return nil, err
 	}
 
-	if message != nil {
-		if err = conn.WriteMessage(messageType, message); err != nil {
-			logger.Error(ctx, err)
+	c := &WebSocketConn{
+		conn:     conn,
+		response: response,
+		options:  options,
+		closed:   make(chan struct{}),
+	}
 
-			if err := response.Body.Close(); err != nil {
-				logger.Error(ctx, err)
-			}
+	c.extendReadDeadline()
 
-			if err := conn.Close(); err != nil {
+	// 收到pong时刷新读超时, ping由gorilla默认回复pong
+	conn.SetPongHandler(func(string) error {
+		c.extendReadDeadline()
+		return nil
+	})
+
+	if message != nil {
+		if err = c.WriteMessage(ctx, messageType, message); err != nil {
+
+			if err := c.Close(); err != nil {
 				logger.Error(ctx, err)
 			}
 
@@ -69,34 +110,52 @@ func WebSocketClient(ctx context.Context, wsURL string, requestHeader http.Heade
 		}
 	}
 
-	return &WebSocketConn{
-		conn:     conn,
-		response: response,
-	}, nil
+	if options.PingInterval > 0 {
+		if err = grpool.AddWithRecover(ctx, c.heartbeat, nil); err != nil {
+			logger.Error(ctx, err)
+		}
+	}
+
+	return c, nil
 }
 
+// ReadMessage 对端关闭连接时返回*websocket.CloseError, 可通过IsNormalClose判断是否正常关闭
 func (c *WebSocketConn) ReadMessage(ctx context.Context) (int, []byte, error) {
 
-	for {
+	messageType, message, err := c.conn.ReadMessage()
+	if err != nil {
 
-		messageType, message, err := c.conn.ReadMessage()
-		if err != nil && websocket.IsUnexpectedCloseError(err) {
-			logger.Error(ctx, err)
+		if c.IsClosed() {
+			return 0, nil, ErrWebSocketClosed
+		}
 
-			if err := c.Close(); err != nil {
-				logger.Error(ctx, err)
-			}
+		if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			logger.Error(ctx, err)
+		}
 
-			return 0, nil, err
+		if err := c.close(websocket.CloseNormalClosure, ""); err != nil && !errors.Is(err, net.ErrClosed) {
+			logger.Error(ctx, err)
 		}
 
-		return messageType, message, nil
+		return 0, nil, err
 	}
+
+	c.extendReadDeadline()
+
+	return messageType, message, nil
 }
 
 func (c *WebSocketConn) WriteMessage(ctx context.Context, messageType int, message []byte) error {
 
 	if messageType != 0 && message != nil {
+
+		c.writeMutex.Lock()
+		defer c.writeMutex.Unlock()
+
+		if c.options.WriteTimeout > 0 {
+			_ = c.conn.SetWriteDeadline(time.Now().Add(c.options.WriteTimeout))
+		}
+
 		if err := c.conn.WriteMessage(messageType, message); err != nil {
 			logger.Error(ctx, err)
 			return err
@@ -109,6 +168,14 @@ func (c *WebSocketConn) WriteMessage(ctx context.Context, messageType int, messa
 func (c *WebSocketConn) WriteJSON(ctx context.Context, message any) error {
 
 	if message != nil {
+
+		c.writeMutex.Lock()
+		defer c.writeMutex.Unlock()
+
+		if c.options.WriteTimeout > 0 {
+			_ = c.conn.SetWriteDeadline(time.Now().Add(c.options.WriteTimeout))
+		}
+
 		if err := c.conn.WriteJSON(message); err != nil {
 			logger.Error(ctx, err)
 			return err
@@ -118,15 +185,99 @@ func (c *WebSocketConn) WriteJSON(ctx context.Context, message any) error {
 	return nil
 }
 
-func (c *WebSocketConn) Close() (err error) {
+// Close 发送正常关闭帧后关闭连接
+func (c *WebSocketConn) Close() error {
+	return c.CloseWithCode(websocket.CloseNormalClosure, "")
+}
+
+// CloseWithCode 发送指定关闭码的关闭帧后关闭连接, 可重复调用
+func (c *WebSocketConn) CloseWithCode(code int, text string) error {
+	c.isClosed.Store(true)
+	return c.close(code, text)
+}
+
+func (c *WebSocketConn) close(code int, text string) (err error) {
+
+	c.closeOnce.Do(func() {
+
+		close(c.closed)
+
+		// 关闭帧发送失败不影响关闭连接, 对端可能已断开
+		_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(time.Second))
+
+		if e := c.response.Body.Close(); e != nil {
+			err = e
+		}
+
+		if e := c.conn.Close(); e != nil {
+			err = e
+		}
+	})
+
+	return err
+}
+
+// IsClosed 连接是否已被本端主动关闭
+func (c *WebSocketConn) IsClosed() bool {
+	return c.isClosed.Load()
+}
 
-	if e := c.response.Body.Close(); e != nil {
-		err = e
+func (c *WebSocketConn) heartbeat(ctx context.Context) {
+
+	ticker := time.NewTicker(c.options.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			// WriteControl可与其它写操作并发调用
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.options.PingInterval)); err != nil {
+				if !c.isDone() {
+					logger.Errorf(ctx, "WebSocketConn heartbeat error: %v", err)
+				}
+				return
+			}
+		}
 	}
+}
 
-	if e := c.conn.Close(); e != nil {
-		err = e
+// 连接是否已关闭, 包括读错误导致的关闭
+func (c *WebSocketConn) isDone() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
 	}
+}
 
-	return err
+func (c *WebSocketConn) extendReadDeadline() {
+	if c.options.ReadTimeout > 0 {
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.options.ReadTimeout))
+	}
+}
+
+// CloseCode 获取关闭码, 非关闭错误返回0
+func CloseCode(err error) int {
+
+	closeError := new(websocket.CloseError)
+	if errors.As(err, &closeError) {
+		return closeError.Code
+	}
+
+	return 0
+}
+
+// IsNormalClose 是否为正常关闭, 包括本端主动关闭
+func IsNormalClose(err error) bool {
+
+	if errors.Is(err, ErrWebSocketClosed) {
+		return true
+	}
+
+	code := CloseCode(err)
+
+	return code == websocket.CloseNormalClosure || code == websocket.CloseGoingAway
 }
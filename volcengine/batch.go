@@ -2,26 +2,209 @@ package volcengine
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
 )
 
+const batchListPageSize = 100 // OpenAPI单页最大数量
+
 func (v *VolcEngine) BatchCreate(ctx context.Context, request model.BatchCreateRequest) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchCreate VolcEngine model: %s start", v.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchCreate VolcEngine model: %s totalTime: %d ms", v.Model, response.TotalTime)
+	}()
+
+	data, err := v.ConvBatchCreateRequest(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "BatchCreate VolcEngine ConvBatchCreateRequest error: %v", err)
+		return response, err
+	}
+
+	bytes, err := v.openApi(ctx, "CreateBatchInferenceJob", data.Bytes())
+	if err != nil {
+		logger.Errorf(ctx, "BatchCreate VolcEngine model: %s, error: %v", v.Model, err)
+		return response, err
+	}
+
+	if response, err = v.ConvBatchResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "BatchCreate VolcEngine ConvBatchResponse error: %v", err)
+		return response, err
+	}
+
+	// 创建任务仅返回任务ID, 其它信息使用请求参数填充
+	response.Endpoint = request.Endpoint
+	response.Model = v.Model
+	response.InputFileId = request.InputFileId
+	response.CompletionWindow = request.CompletionWindow
+	response.Status = "validating"
+	response.CreatedAt = gtime.Timestamp()
+	response.Metadata = request.Metadata
+
+	logger.Infof(ctx, "BatchCreate VolcEngine model: %s finished, id: %s", v.Model, response.Id)
+
+	return response, nil
 }
 
 func (v *VolcEngine) BatchList(ctx context.Context, request model.BatchListRequest) (response model.BatchListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchList VolcEngine model: %s start", v.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchList VolcEngine model: %s totalTime: %d ms", v.Model, response.TotalTime)
+	}()
+
+	limit := int(request.Limit)
+	if limit <= 0 {
+		limit = 20
+	}
+
+	response = model.BatchListResponse{
+		Object: "list",
+		Data:   make([]any, 0),
+	}
+
+	// OpenAPI使用页码分页, 按创建时间倒序, after为任务ID时从第一页开始查找其位置, 多取一条判断是否还有更多
+	isFound := request.After == ""
+
+	for page := 1; len(response.Data) <= limit; page++ {
+
+		bytes, err := v.openApi(ctx, "ListBatchInferenceJobs", gjson.MustEncode(model.VolcBatchListReq{
+			PageNumber: page,
+			PageSize:   batchListPageSize,
+		}))
+		if err != nil {
+			logger.Errorf(ctx, "BatchList VolcEngine model: %s, error: %v", v.Model, err)
+			return response, err
+		}
+
+		batchListRes, err := v.ConvBatchListResponse(ctx, bytes)
+		if err != nil {
+			logger.Errorf(ctx, "BatchList VolcEngine ConvBatchListResponse error: %v", err)
+			return response, err
+		}
+
+		for _, batch := range batchListRes.Data {
+
+			if !isFound {
+				isFound = batch.(model.BatchResponse).Id == request.After
+				continue
+			}
+
+			if len(response.Data) <= limit {
+				response.Data = append(response.Data, batch)
+			}
+		}
+
+		if !batchListRes.HasMore {
+			break
+		}
+	}
+
+	if !isFound {
+		return response, errors.New(fmt.Sprintf("VolcEngine batch %s not found", request.After))
+	}
+
+	if response.HasMore = len(response.Data) > limit; response.HasMore {
+		response.Data = response.Data[:limit]
+	}
+
+	if len(response.Data) > 0 {
+		firstId := response.Data[0].(model.BatchResponse).Id
+		lastId := response.Data[len(response.Data)-1].(model.BatchResponse).Id
+		response.FirstId = &firstId
+		response.LastId = &lastId
+	}
+
+	logger.Infof(ctx, "BatchList VolcEngine model: %s finished", v.Model)
+
+	return response, nil
 }
 
 func (v *VolcEngine) BatchRetrieve(ctx context.Context, request model.BatchRetrieveRequest) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchRetrieve VolcEngine model: %s start", v.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchRetrieve VolcEngine model: %s totalTime: %d ms", v.Model, response.TotalTime)
+	}()
+
+	// OpenAPI没有单个任务的查询接口, 按任务ID过滤列表
+	batchListReq := model.VolcBatchListReq{
+		PageNumber: 1,
+		PageSize:   1,
+		Filter: &model.VolcBatchFilter{
+			Ids: []string{request.BatchId},
+		},
+	}
+
+	bytes, err := v.openApi(ctx, "ListBatchInferenceJobs", gjson.MustEncode(batchListReq))
+	if err != nil {
+		logger.Errorf(ctx, "BatchRetrieve VolcEngine model: %s, error: %v", v.Model, err)
+		return response, err
+	}
+
+	batchListRes, err := v.ConvBatchListResponse(ctx, bytes)
+	if err != nil {
+		logger.Errorf(ctx, "BatchRetrieve VolcEngine ConvBatchListResponse error: %v", err)
+		return response, err
+	}
+
+	for _, batch := range batchListRes.Data {
+		if batchRes, ok := batch.(model.BatchResponse); ok && batchRes.Id == request.BatchId {
+
+			response = batchRes
+			response.ResponseBytes = bytes
+
+			logger.Infof(ctx, "BatchRetrieve VolcEngine model: %s finished", v.Model)
+
+			return response, nil
+		}
+	}
+
+	return response, errors.New(fmt.Sprintf("VolcEngine batch %s not found", request.BatchId))
 }
 
 func (v *VolcEngine) BatchCancel(ctx context.Context, request model.BatchCancelRequest) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchCancel VolcEngine model: %s start", v.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchCancel VolcEngine model: %s totalTime: %d ms", v.Model, response.TotalTime)
+	}()
+
+	bytes, err := v.openApi(ctx, "CancelBatchInferenceJob", gjson.MustEncode(model.VolcBatchCancelReq{Id: request.BatchId}))
+	if err != nil {
+		logger.Errorf(ctx, "BatchCancel VolcEngine model: %s, error: %v", v.Model, err)
+		return response, err
+	}
+
+	if _, err = v.ConvBatchResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "BatchCancel VolcEngine ConvBatchResponse error: %v", err)
+		return response, err
+	}
+
+	// 取消接口不返回任务信息, 需重新获取
+	if response, err = v.BatchRetrieve(ctx, model.BatchRetrieveRequest{BatchId: request.BatchId}); err != nil {
+		logger.Errorf(ctx, "BatchCancel VolcEngine BatchRetrieve error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "BatchCancel VolcEngine model: %s finished", v.Model)
+
+	return response, nil
 }
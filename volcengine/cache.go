@@ -0,0 +1,44 @@
+package volcengine
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
+	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
+)
+
+func (v *VolcEngine) CacheCreate(ctx context.Context, request model.CacheCreateRequest) (response model.CacheResponse, err error) {
+
+	logger.Infof(ctx, "CacheCreate VolcEngine model: %s start", v.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "CacheCreate VolcEngine model: %s totalTime: %d ms", v.Model, response.TotalTime)
+	}()
+
+	data, err := v.ConvCacheCreateRequest(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "CacheCreate VolcEngine ConvCacheCreateRequest error: %v", err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpPost(ctx, v.BaseUrl+"/context/create", v.header, data, nil, v.Timeout, v.ProxyUrl, v.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "CacheCreate VolcEngine model: %s, error: %v", v.Model, err)
+		return response, err
+	}
+
+	if response, err = v.ConvCacheResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "CacheCreate VolcEngine ConvCacheResponse error: %v", err)
+		return response, err
+	}
+
+	response.DisplayName = request.DisplayName
+
+	logger.Infof(ctx, "CacheCreate VolcEngine model: %s finished, id: %s", v.Model, response.Id)
+
+	return response, nil
+}
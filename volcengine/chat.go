@@ -24,10 +24,24 @@ func (v *VolcEngine) ChatCompletions(ctx context.Context, data any) (response mo
 	}()
 
 	if !slices.Contains(v.ReqPassthroughParams, "req_data") {
-		if data, err = v.ConvChatCompletionsRequest(ctx, data); err != nil {
+
+		request, err := v.ConvChatCompletionsRequest(ctx, data)
+		if err != nil {
 			logger.Errorf(ctx, "ChatCompletions VolcEngine ConvChatCompletionsRequest error: %v", err)
 			return response, err
 		}
+
+		data = request
+
+		// 引用上下文缓存时使用上下文对话接口
+		if request.CacheId != "" {
+
+			data = v.convContextChatRequest(request)
+
+			if v.Path == "" {
+				v.Path = "/context/chat/completions"
+			}
+		}
 	}
 
 	if v.Path == "" {
@@ -64,10 +78,24 @@ func (v *VolcEngine) ChatCompletionsStream(ctx context.Context, data any) (respo
 	}()
 
 	if !slices.Contains(v.ReqPassthroughParams, "req_data") {
-		if data, err = v.ConvChatCompletionsRequest(ctx, data); err != nil {
+
+		request, err := v.ConvChatCompletionsRequest(ctx, data)
+		if err != nil {
 			logger.Errorf(ctx, "ChatCompletionsStream VolcEngine ConvChatCompletionsRequest error: %v", err)
 			return nil, err
 		}
+
+		data = request
+
+		// 引用上下文缓存时使用上下文对话接口
+		if request.CacheId != "" {
+
+			data = v.convContextChatRequest(request)
+
+			if v.Path == "" {
+				v.Path = "/context/chat/completions"
+			}
+		}
 	}
 
 	if v.Path == "" {
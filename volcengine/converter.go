@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/os/gtime"
@@ -12,6 +14,7 @@ import (
 	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/iimeta/fastapi-sdk/v2/common"
 	"github.com/iimeta/fastapi-sdk/v2/consts"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
 )
@@ -37,12 +40,17 @@ func (v *VolcEngine) ConvChatCompletionsRequest(ctx context.Context, data any) (
 		}
 	}
 
+	isSupportSystemRole := true
 	if v.IsSupportSystemRole != nil {
-		request.Messages = common.HandleMessages(request.Messages, *v.IsSupportSystemRole)
-	} else {
-		request.Messages = common.HandleMessages(request.Messages, true)
+		isSupportSystemRole = *v.IsSupportSystemRole
 	}
 
+	// 豆包兼容OpenAI格式, 仅合并连续相同角色的消息
+	request.Messages = common.HandleMessagesWithOptions(request.Messages, common.MessagesOptions{
+		IsSupportSystemRole: isSupportSystemRole,
+		IsMergeSameRole:     true,
+	})
+
 	return request, nil
 }
 
@@ -64,6 +72,11 @@ func (v *VolcEngine) ConvChatCompletionsResponse(ctx context.Context, data []byt
 		response.Id = consts.COMPLETION_ID_PREFIX + response.Id
 	}
 
+	// 上下文缓存命中的token数
+	if response.Usage != nil {
+		response.Usage.InputTokensDetails.CachedTokens = response.Usage.PromptTokensDetails.CachedTokens
+	}
+
 	return response, nil
 }
 
@@ -85,6 +98,11 @@ func (v *VolcEngine) ConvChatCompletionsStreamResponse(ctx context.Context, data
 		response.Id = consts.COMPLETION_ID_PREFIX + response.Id
 	}
 
+	// 上下文缓存命中的token数
+	if response.Usage != nil {
+		response.Usage.InputTokensDetails.CachedTokens = response.Usage.PromptTokensDetails.CachedTokens
+	}
+
 	return response, nil
 }
 
@@ -104,23 +122,114 @@ func (v *VolcEngine) ConvChatResponsesStreamResponse(ctx context.Context, data [
 }
 
 func (v *VolcEngine) ConvImageGenerationsRequest(ctx context.Context, data []byte) (request model.ImageGenerationRequest, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvImageGenerationsRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	if err = json.Unmarshal(data, &request); err != nil {
+		logger.Error(ctx, err)
+		return request, err
+	}
+
+	return request, nil
 }
 
 func (v *VolcEngine) ConvImageGenerationsResponse(ctx context.Context, data []byte) (response model.ImageResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvImageGenerationsResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	imageRes := model.VolcImageRes{}
+	if err = json.Unmarshal(data, &imageRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	if imageRes.Error != nil && imageRes.Error.Code != "" {
+		err = errors.NewApiError(500, imageRes.Error.Code, imageRes.Error.Message, "api_error", "")
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	response = model.ImageResponse{
+		Created:       imageRes.Created,
+		Usage:         convImageUsage(imageRes.Usage),
+		ResponseBytes: data,
+	}
+
+	for _, image := range imageRes.Data {
+
+		// 组图时部分图片可能生成失败, 仅返回成功的图片
+		if image.Error != nil {
+			logger.Errorf(ctx, "ConvImageGenerationsResponse VolcEngine model: %s, image error: %s", v.Model, gjson.MustEncodeString(image.Error))
+			continue
+		}
+
+		response.Data = append(response.Data, model.ImageResponseData{
+			Url:     image.Url,
+			B64Json: image.B64Json,
+		})
+	}
+
+	return response, nil
+}
+
+func (v *VolcEngine) ConvImageGenerationsStreamResponse(ctx context.Context, data []byte) (response model.ImageResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvImageGenerationsStreamResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	response.ResponseBytes = data
+
+	streamRes := model.VolcImageStreamRes{}
+	if err = json.Unmarshal(data, &streamRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	response.Created = streamRes.Created
+	response.Event = streamRes.Type
+
+	switch streamRes.Type {
+	case "image_generation.partial_succeeded":
+		response.Data = []model.ImageResponseData{{
+			Url:     streamRes.Url,
+			B64Json: streamRes.B64Json,
+		}}
+	case "image_generation.partial_failed":
+		// 单张图片失败不中断流, 后续图片仍会继续生成
+		logger.Errorf(ctx, "ConvImageGenerationsStreamResponse VolcEngine model: %s, image_index: %d, error: %s", v.Model, streamRes.ImageIndex, gjson.MustEncodeString(streamRes.Error))
+	case "image_generation.completed":
+		response.Usage = convImageUsage(streamRes.Usage)
+	}
+
+	return response, nil
 }
 
 func (v *VolcEngine) ConvImageEditsRequest(ctx context.Context, request model.ImageEditRequest) (data *bytes.Buffer, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvImageEditsRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	imageReq, err := v.ConvImageEditsRequestOfficial(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "ConvImageEditsRequest VolcEngine model: %s, error: %v", v.Model, err)
+		return data, err
+	}
+
+	return bytes.NewBuffer(imageReq), nil
 }
 
 func (v *VolcEngine) ConvImageEditsResponse(ctx context.Context, data []byte) (response model.ImageResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return v.ConvImageGenerationsResponse(ctx, data)
 }
 
 func (v *VolcEngine) ConvAudioSpeechRequest(ctx context.Context, data []byte) (request model.SpeechRequest, err error) {
@@ -144,13 +253,57 @@ func (v *VolcEngine) ConvAudioTranscriptionsResponse(ctx context.Context, data [
 }
 
 func (v *VolcEngine) ConvTextEmbeddingsRequest(ctx context.Context, data []byte) (request model.EmbeddingRequest, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvTextEmbeddingsRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	if err = json.Unmarshal(data, &request); err != nil {
+		logger.Error(ctx, err)
+		return request, err
+	}
+
+	return request, nil
 }
 
 func (v *VolcEngine) ConvTextEmbeddingsResponse(ctx context.Context, data []byte) (response model.EmbeddingResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvTextEmbeddingsResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	// 多模态向量化的data为对象, 文本向量化为数组
+	if !gjson.New(data).Get("data").IsMap() {
+
+		if err = json.Unmarshal(data, &response); err != nil {
+			logger.Error(ctx, err)
+			return response, err
+		}
+
+		return response, nil
+	}
+
+	embeddingRes := model.VolcMultimodalEmbeddingRes{}
+	if err = json.Unmarshal(data, &embeddingRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	// 每次请求只有一个输入, 按OpenAI的格式返回
+	response = model.EmbeddingResponse{
+		Object: "list",
+		Data: []any{map[string]any{
+			"object":    "embedding",
+			"index":     0,
+			"embedding": embeddingRes.Data.Embedding,
+		}},
+		Model: embeddingRes.Model,
+		Usage: embeddingRes.Usage,
+	}
+
+	return response, nil
 }
 
 func (v *VolcEngine) ConvVideoCreateRequest(ctx context.Context, request model.VideoCreateRequest) (*bytes.Buffer, error) {
@@ -289,6 +442,74 @@ func (v *VolcEngine) ConvVideoContentResponse(ctx context.Context, data []byte)
 	return model.VideoContentResponse{Data: data}, nil
 }
 
+func (v *VolcEngine) ConvCacheCreateRequest(ctx context.Context, request model.CacheCreateRequest) ([]byte, error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvCacheCreateRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	contextCreateReq := model.VolcContextCreateReq{
+		Model:    v.Model,
+		Messages: request.Messages,
+		Mode:     request.Mode,
+		Ttl:      request.Ttl,
+	}
+
+	if contextCreateReq.Mode == "" {
+		contextCreateReq.Mode = "session"
+	}
+
+	// 火山引擎仅支持ttl, 过期时间需转换为剩余秒数
+	if request.ExpiresAt > 0 {
+		contextCreateReq.Ttl = max(request.ExpiresAt-gtime.Timestamp(), 1)
+	}
+
+	return gjson.MustEncode(contextCreateReq), nil
+}
+
+func (v *VolcEngine) ConvCacheResponse(ctx context.Context, data []byte) (response model.CacheResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvCacheResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	contextCreateRes := model.VolcContextCreateRes{}
+	if err = json.Unmarshal(data, &contextCreateRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	response = model.CacheResponse{
+		Id:            contextCreateRes.Id,
+		Object:        "cache",
+		Model:         contextCreateRes.Model,
+		CreatedAt:     gtime.Timestamp(),
+		Usage:         contextCreateRes.Usage,
+		ResponseBytes: data,
+	}
+
+	if contextCreateRes.Ttl > 0 {
+		response.ExpiresAt = response.CreatedAt + contextCreateRes.Ttl
+	}
+
+	return response, nil
+}
+
+// 引用上下文缓存时, 缓存ID作为context_id传入
+func (v *VolcEngine) convContextChatRequest(request model.ChatCompletionRequest) model.VolcContextChatReq {
+
+	contextChatReq := model.VolcContextChatReq{
+		ChatCompletionRequest: request,
+		ContextId:             request.CacheId,
+	}
+
+	contextChatReq.CacheId = ""
+
+	return contextChatReq
+}
+
 func (v *VolcEngine) ConvFileUploadRequest(ctx context.Context, request model.FileUploadRequest) (data *bytes.Buffer, err error) {
 	//TODO implement me
 	panic("implement me")
@@ -310,18 +531,101 @@ func (v *VolcEngine) ConvFileResponse(ctx context.Context, data []byte) (respons
 }
 
 func (v *VolcEngine) ConvBatchCreateRequest(ctx context.Context, request model.BatchCreateRequest) (data *bytes.Buffer, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchCreateRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	metadata := gconv.Map(request.Metadata)
+
+	batchCreateReq := model.VolcBatchCreateReq{
+		Name:             gconv.String(metadata["name"]),
+		Description:      gconv.String(metadata["description"]),
+		ModelReference:   convModelReference(v.Model),
+		CompletionWindow: convCompletionWindow(request.CompletionWindow),
+	}
+
+	if batchCreateReq.Name == "" {
+		batchCreateReq.Name = fmt.Sprintf("batch-%d", gtime.Timestamp())
+	}
+
+	// 批量推理从TOS读取输入文件, input_file_id格式为 tos://bucket/key
+	if batchCreateReq.InputFileTosLocation, err = convTosLocation(request.InputFileId); err != nil {
+		return nil, err
+	}
+
+	// 输出目录默认为输入文件所在桶的 batch_output/ 目录
+	if outputDir := gconv.String(metadata["output_dir"]); outputDir != "" {
+		if batchCreateReq.OutputDirTosLocation, err = convTosLocation(outputDir); err != nil {
+			return nil, err
+		}
+	} else {
+		batchCreateReq.OutputDirTosLocation = model.VolcTosLocation{
+			BucketName: batchCreateReq.InputFileTosLocation.BucketName,
+			ObjectKey:  "batch_output/",
+		}
+	}
+
+	return bytes.NewBuffer(gjson.MustEncode(batchCreateReq)), nil
 }
 
 func (v *VolcEngine) ConvBatchListResponse(ctx context.Context, data []byte) (response model.BatchListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchListResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	batchListRes := model.VolcBatchListRes{}
+	if err = json.Unmarshal(data, &batchListRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	if err = convResponseMetadataError(batchListRes.ResponseMetadata); err != nil {
+		return response, err
+	}
+
+	response = model.BatchListResponse{
+		Object:  "list",
+		Data:    make([]any, 0),
+		HasMore: batchListRes.Result.PageNumber*batchListRes.Result.PageSize < batchListRes.Result.TotalCount,
+	}
+
+	for _, job := range batchListRes.Result.Items {
+		batchRes := convBatchJob(job)
+		response.Data = append(response.Data, batchRes)
+		if response.FirstId == nil {
+			response.FirstId = &batchRes.Id
+		}
+		response.LastId = &batchRes.Id
+	}
+
+	return response, nil
 }
 
 func (v *VolcEngine) ConvBatchResponse(ctx context.Context, data []byte) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	batchRes := model.VolcBatchRes{}
+	if err = json.Unmarshal(data, &batchRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	if err = convResponseMetadataError(batchRes.ResponseMetadata); err != nil {
+		return response, err
+	}
+
+	response = convBatchJob(batchRes.Result)
+	response.ResponseBytes = data
+
+	return response, nil
 }
 
 // 将火山引擎状态映射到系统标准状态
@@ -385,9 +689,206 @@ func convSizeToRatioResolution(size string) (ratio, resolution string) {
 	return ratio, resolution
 }
 
+// Seedream推荐的宽高比对应尺寸
+var imageSizes = map[string]map[string]string{
+	"1K": {"1:1": "1024x1024", "4:3": "1152x864", "3:4": "864x1152", "16:9": "1280x720", "9:16": "720x1280", "3:2": "1248x832", "2:3": "832x1248", "21:9": "1512x648"},
+	"2K": {"1:1": "2048x2048", "4:3": "2304x1728", "3:4": "1728x2304", "16:9": "2560x1440", "9:16": "1440x2560", "3:2": "2496x1664", "2:3": "1664x2496", "21:9": "3024x1296"},
+}
+
+// size/aspect_ratio转换为Seedream的size, 支持 1K/2K/4K 和 宽x高 两种格式
+func convImageSize(size, aspectRatio string) string {
+
+	if size == "" || size == "auto" {
+		if aspectRatio == "" {
+			return ""
+		}
+		size = "2K"
+	}
+
+	if level := gstr.ToUpper(size); level == "1K" || level == "2K" || level == "4K" {
+		if recommended, ok := imageSizes[level][aspectRatio]; ok {
+			return recommended
+		}
+		return level
+	}
+
+	ratio, resolution := convSizeToRatioResolution(size)
+	if ratio == "" {
+		return size
+	}
+
+	// 低于720p的尺寸(如 256x256、512x512)不满足Seedream的最小像素要求, 按宽高比使用推荐尺寸
+	if resolution != "720p" && resolution != "1080p" && resolution != "4k" {
+		if recommended, ok := imageSizes["1K"][ratio]; ok {
+			return recommended
+		}
+	}
+
+	return gstr.ReplaceByArray(size, []string{"X", "x", "×", "x", "*", "x"})
+}
+
+func convImageUsage(usage *model.VolcImageUsage) model.Usage {
+
+	if usage == nil {
+		return model.Usage{}
+	}
+
+	return model.Usage{
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      usage.TotalTokens,
+		OutputTokens:     usage.OutputTokens,
+	}
+}
+
 func gcd(a, b int) int {
 	for b != 0 {
 		a, b = b, a%b
 	}
 	return a
 }
+
+// 模型名称如 doubao-1-5-pro-32k-250115, 末尾6位数字为模型版本
+func convModelReference(name string) model.VolcModelReference {
+
+	foundationModel := &model.VolcFoundationModel{Name: name}
+
+	if i := strings.LastIndex(name, "-"); i > 0 && len(name)-i-1 == 6 && gstr.IsNumeric(name[i+1:]) {
+		foundationModel.Name = name[:i]
+		foundationModel.ModelVersion = name[i+1:]
+	}
+
+	return model.VolcModelReference{FoundationModel: foundationModel}
+}
+
+// 解析TOS存储位置, 支持 tos://bucket/key 和 bucket/key
+func convTosLocation(location string) (model.VolcTosLocation, error) {
+
+	bucket, key, found := strings.Cut(strings.TrimPrefix(location, "tos://"), "/")
+	if !found || bucket == "" || key == "" {
+		return model.VolcTosLocation{}, errors.New(fmt.Sprintf("VolcEngine invalid TOS location: %s, expected tos://bucket/key", location))
+	}
+
+	return model.VolcTosLocation{BucketName: bucket, ObjectKey: key}, nil
+}
+
+// 完成时间窗口, OpenAI为24h, 火山引擎以天为单位, 如1d
+func convCompletionWindow(completionWindow string) string {
+
+	if hours, found := strings.CutSuffix(completionWindow, "h"); found && gstr.IsNumeric(hours) {
+		return fmt.Sprintf("%dd", max(gconv.Int(hours)/24, 1))
+	}
+
+	return completionWindow
+}
+
+func convResponseMetadataError(responseMetadata model.VolcResponseMetadata) error {
+
+	if responseMetadata.Error != nil {
+		return errors.New(fmt.Sprintf("VolcEngine %s error, code: %s, message: %s", responseMetadata.Action, responseMetadata.Error.Code, responseMetadata.Error.Message))
+	}
+
+	return nil
+}
+
+func convBatchJob(job model.VolcBatchJob) model.BatchResponse {
+
+	batchRes := model.BatchResponse{
+		Id:               job.Id,
+		Object:           "batch",
+		CompletionWindow: job.CompletionWindow,
+		Status:           convBatchStatus(job.Status.Phase),
+		RequestCounts: model.RequestCounts{
+			Total:     job.RequestCounts.Total,
+			Completed: job.RequestCounts.Completed,
+			Failed:    job.RequestCounts.Failed,
+		},
+		Metadata: map[string]any{
+			"name":        job.Name,
+			"description": job.Description,
+		},
+	}
+
+	if job.ModelReference.FoundationModel != nil {
+		batchRes.Model = job.ModelReference.FoundationModel.Name
+		if job.ModelReference.FoundationModel.ModelVersion != "" {
+			batchRes.Model += "-" + job.ModelReference.FoundationModel.ModelVersion
+		}
+	} else {
+		batchRes.Model = job.ModelReference.CustomModelId
+	}
+
+	// 任务信息不包含接口路径, 按模型推断
+	batchRes.Endpoint = "/v1/chat/completions"
+	if strings.Contains(batchRes.Model, "embedding") {
+		batchRes.Endpoint = "/v1/embeddings"
+	}
+
+	if job.InputFileTosLocation.BucketName != "" {
+		batchRes.InputFileId = fmt.Sprintf("tos://%s/%s", job.InputFileTosLocation.BucketName, job.InputFileTosLocation.ObjectKey)
+	}
+
+	// 结果写入TOS输出目录, 以目录作为输出文件ID
+	if job.OutputDirTosLocation.BucketName != "" {
+		batchRes.OutputFileId = fmt.Sprintf("tos://%s/%s", job.OutputDirTosLocation.BucketName, job.OutputDirTosLocation.ObjectKey)
+	}
+
+	if createTime, err := time.Parse(time.RFC3339, job.CreateTime); err == nil {
+		batchRes.CreatedAt = createTime.Unix()
+	}
+
+	if expireTime, err := time.Parse(time.RFC3339, job.ExpireTime); err == nil {
+		batchRes.ExpiresAt = expireTime.Unix()
+	}
+
+	if phaseTime, err := time.Parse(time.RFC3339, job.Status.PhaseTime); err == nil {
+		switch batchRes.Status {
+		case "in_progress":
+			batchRes.InProgressAt = phaseTime.Unix()
+		case "completed":
+			batchRes.CompletedAt = phaseTime.Unix()
+		case "failed":
+			batchRes.FailedAt = phaseTime.Unix()
+		case "cancelling":
+			batchRes.CancellingAt = phaseTime.Unix()
+		case "cancelled":
+			batchRes.CancelledAt = phaseTime.Unix()
+		}
+	}
+
+	if batchRes.Status == "failed" && job.Status.Message != "" {
+		batchRes.Errors = &model.BatchError{
+			Object: "list",
+			Data: []struct {
+				Code    string `json:"code"`
+				Line    int    `json:"line"`
+				Message string `json:"message"`
+				Param   string `json:"param"`
+			}{{
+				Code:    job.Status.Phase,
+				Message: job.Status.Message,
+			}},
+		}
+	}
+
+	return batchRes
+}
+
+// 批量推理任务状态映射
+func convBatchStatus(phase string) string {
+	switch phase {
+	case "Queued":
+		return "validating"
+	case "Running":
+		return "in_progress"
+	case "Completed":
+		return "completed"
+	case "Failed":
+		return "failed"
+	case "Terminating":
+		return "cancelling"
+	case "Terminated":
+		return "cancelled"
+	default:
+		return gstr.ToLower(phase)
+	}
+}
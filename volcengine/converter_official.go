@@ -2,11 +2,18 @@ package volcengine
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
 
+	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
 	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
 )
@@ -27,8 +34,40 @@ func (v *VolcEngine) ConvChatCompletionsStreamResponseOfficial(ctx context.Conte
 }
 
 func (v *VolcEngine) ConvImageGenerationsRequestOfficial(ctx context.Context, request model.ImageGenerationRequest) ([]byte, error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvImageGenerationsRequestOfficial time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	imageReq := model.VolcImageReq{
+		Model:                     v.Model,
+		Prompt:                    request.Prompt,
+		Size:                      convImageSize(request.Size, request.AspectRatio),
+		Seed:                      request.Seed,
+		SequentialImageGeneration: request.Sequential,
+		Stream:                    request.Stream,
+		GuidanceScale:             request.GuidanceScale,
+		ResponseFormat:            request.ResponseFormat,
+		Watermark:                 request.Watermark,
+	}
+
+	images := make([]string, 0)
+	for _, image := range request.Images {
+		if image.ImageUrl != "" {
+			images = append(images, image.ImageUrl)
+		}
+	}
+
+	if image, ok := request.Image.(string); ok && image != "" {
+		images = append(images, image)
+	}
+
+	imageReq.Image = convImages(images)
+
+	convSequential(&imageReq, request.N)
+
+	return gjson.MustEncode(imageReq), nil
 }
 
 func (v *VolcEngine) ConvImageGenerationsResponseOfficial(ctx context.Context, response model.ImageResponse) ([]byte, error) {
@@ -37,8 +76,109 @@ func (v *VolcEngine) ConvImageGenerationsResponseOfficial(ctx context.Context, r
 }
 
 func (v *VolcEngine) ConvImageEditsRequestOfficial(ctx context.Context, request model.ImageEditRequest) ([]byte, error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvImageEditsRequestOfficial time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	imageReq := model.VolcImageReq{
+		Model:                     v.Model,
+		Prompt:                    request.Prompt,
+		Size:                      convImageSize(request.Size, request.AspectRatio),
+		Seed:                      request.Seed,
+		SequentialImageGeneration: request.Sequential,
+		Stream:                    request.Stream,
+		GuidanceScale:             request.GuidanceScale,
+		ResponseFormat:            request.ResponseFormat,
+		Watermark:                 request.Watermark,
+	}
+
+	// 多张参考图, 支持URL和Base64编码, 不支持OpenAI的file_id
+	images := make([]string, 0)
+	for _, image := range request.Images {
+		if image.ImageUrl != "" {
+			images = append(images, image.ImageUrl)
+		}
+	}
+
+	switch image := request.Image.(type) {
+	case string:
+		if image != "" {
+			images = append(images, image)
+		}
+	case []string:
+		images = append(images, image...)
+	case []any:
+		for _, item := range image {
+			if url, ok := item.(string); ok && url != "" {
+				images = append(images, url)
+			}
+		}
+	case []*multipart.FileHeader:
+		for _, fileHeader := range image {
+
+			file, err := fileHeader.Open()
+			if err != nil {
+				logger.Error(ctx, err)
+				return nil, err
+			}
+
+			fileBytes, err := io.ReadAll(file)
+
+			if err := file.Close(); err != nil {
+				logger.Error(ctx, err)
+			}
+
+			if err != nil {
+				logger.Error(ctx, err)
+				return nil, err
+			}
+
+			mimeType := fileHeader.Header.Get("Content-Type")
+			if mimeType == "" || mimeType == "application/octet-stream" {
+				mimeType = http.DetectContentType(fileBytes)
+			}
+
+			images = append(images, fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(fileBytes)))
+		}
+	}
+
+	if len(images) == 0 {
+		return nil, errors.New("VolcEngine image edits requires at least one image")
+	}
+
+	imageReq.Image = convImages(images)
+
+	convSequential(&imageReq, request.N)
+
+	return gjson.MustEncode(imageReq), nil
+}
+
+// 单张参考图为字符串, 多张为数组
+func convImages(images []string) any {
+
+	switch len(images) {
+	case 0:
+		return nil
+	case 1:
+		return images[0]
+	}
+
+	return images
+}
+
+// n大于1时开启组图, 由模型生成最多n张图片
+func convSequential(imageReq *model.VolcImageReq, n int) {
+
+	if n <= 1 || imageReq.SequentialImageGeneration == "disabled" {
+		return
+	}
+
+	imageReq.SequentialImageGeneration = "auto"
+	imageReq.SequentialImageGenerationOptions = &model.VolcSequentialImageGenerationOptions{
+		MaxImages: n,
+	}
 }
 
 func (v *VolcEngine) ConvImageEditsResponseOfficial(ctx context.Context, response model.ImageResponse) ([]byte, error) {
@@ -46,6 +186,101 @@ func (v *VolcEngine) ConvImageEditsResponseOfficial(ctx context.Context, respons
 	panic("implement me")
 }
 
+func (v *VolcEngine) ConvTextEmbeddingsRequestOfficial(ctx context.Context, request model.EmbeddingRequest) ([]byte, error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvTextEmbeddingsRequestOfficial time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	inputs, err := convEmbeddingInputs(request.Input)
+	if err != nil {
+		logger.Errorf(ctx, "ConvTextEmbeddingsRequestOfficial VolcEngine model: %s, error: %v", v.Model, err)
+		return nil, err
+	}
+
+	embeddingReq := model.VolcEmbeddingReq{
+		Model:          v.Model,
+		EncodingFormat: request.EncodingFormat,
+		Dimensions:     request.Dimensions,
+	}
+
+	if isMultimodalEmbedding(v.Model, inputs) {
+
+		// 多模态向量化会将多个输入融合为一个向量, 无法按输入返回多个向量
+		if len(inputs) > 1 {
+			err = errors.New("VolcEngine multimodal embedding supports only one input per request")
+			logger.Errorf(ctx, "ConvTextEmbeddingsRequestOfficial VolcEngine model: %s, error: %v", v.Model, err)
+			return nil, err
+		}
+
+		embeddingReq.Input = inputs
+		return gjson.MustEncode(embeddingReq), nil
+	}
+
+	texts := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		texts = append(texts, input.Text)
+	}
+
+	embeddingReq.Input = texts
+
+	return gjson.MustEncode(embeddingReq), nil
+}
+
+// 模型为多模态向量化模型或输入包含图片、视频时使用多模态向量化
+func isMultimodalEmbedding(modelName string, inputs []model.EmbeddingInput) bool {
+
+	if gstr.Contains(modelName, "vision") {
+		return true
+	}
+
+	for _, input := range inputs {
+		if input.Type != "text" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// input统一转换为多模态输入项, 支持字符串、字符串数组和 {"type": "image_url", "image_url": {"url": ""}} 格式的数组
+func convEmbeddingInputs(input any) ([]model.EmbeddingInput, error) {
+
+	var items []any
+	switch value := input.(type) {
+	case string:
+		return []model.EmbeddingInput{{Type: "text", Text: value}}, nil
+	case map[string]any:
+		items = []any{value}
+	default:
+		items = gconv.Interfaces(value)
+	}
+
+	inputs := make([]model.EmbeddingInput, 0, len(items))
+
+	for _, item := range items {
+
+		if text, ok := item.(string); ok {
+			inputs = append(inputs, model.EmbeddingInput{Type: "text", Text: text})
+			continue
+		}
+
+		embeddingInput := model.EmbeddingInput{}
+		if err := json.Unmarshal(gjson.MustEncode(item), &embeddingInput); err != nil {
+			return nil, err
+		}
+
+		if embeddingInput.Type == "" {
+			return nil, errors.New(fmt.Sprintf("unsupported embedding input: %s", gjson.MustEncodeString(item)))
+		}
+
+		inputs = append(inputs, embeddingInput)
+	}
+
+	return inputs, nil
+}
+
 func (v *VolcEngine) ConvVideoJobResponseOfficial(ctx context.Context, response model.VideoJobResponse) (*model.VolcVideoTaskRes, error) {
 
 	now := gtime.TimestampMilli()
@@ -2,11 +2,136 @@ package volcengine
 
 import (
 	"context"
+	"net/http"
+	"slices"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (v *VolcEngine) TextEmbeddings(ctx context.Context, data []byte) (response model.EmbeddingResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "TextEmbeddings VolcEngine model: %s start", v.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "TextEmbeddings VolcEngine model: %s totalTime: %d ms", v.Model, response.TotalTime)
+	}()
+
+	// req_data透传时按模型名称判断是否为多模态向量化
+	isMultimodal := gstr.Contains(v.Model, "vision")
+
+	if !slices.Contains(v.ReqPassthroughParams, "req_data") {
+
+		request, err := v.ConvTextEmbeddingsRequest(ctx, data)
+		if err != nil {
+			logger.Errorf(ctx, "TextEmbeddings VolcEngine ConvTextEmbeddingsRequest error: %v", err)
+			return response, err
+		}
+
+		inputs, err := convEmbeddingInputs(request.Input)
+		if err != nil {
+			logger.Errorf(ctx, "TextEmbeddings VolcEngine model: %s, error: %v", v.Model, err)
+			return response, err
+		}
+
+		// 多模态向量化会将多个输入融合为一个向量, 逐个输入请求以返回与输入数量一致的向量
+		if isMultimodal = isMultimodalEmbedding(v.Model, inputs); isMultimodal && len(inputs) > 1 {
+			return v.multimodalEmbeddings(ctx, request, inputs)
+		}
+
+		if data, err = v.ConvTextEmbeddingsRequestOfficial(ctx, request); err != nil {
+			logger.Errorf(ctx, "TextEmbeddings VolcEngine ConvTextEmbeddingsRequestOfficial error: %v", err)
+			return response, err
+		}
+	}
+
+	bytes, responseHeader, err := v.embeddings(ctx, data, isMultimodal)
+	if err != nil {
+		logger.Errorf(ctx, "TextEmbeddings VolcEngine model: %s, error: %v", v.Model, err)
+		return response, err
+	}
+
+	if response, err = v.ConvTextEmbeddingsResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "TextEmbeddings VolcEngine ConvTextEmbeddingsResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseBytes = bytes
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "TextEmbeddings VolcEngine model: %s finished", v.Model)
+
+	return response, nil
+}
+
+// 每个输入单独请求多模态向量化, 合并为与输入顺序一致的多个向量, 用量累加
+func (v *VolcEngine) multimodalEmbeddings(ctx context.Context, request model.EmbeddingRequest, inputs []model.EmbeddingInput) (response model.EmbeddingResponse, err error) {
+
+	response = model.EmbeddingResponse{
+		Object: "list",
+		Usage:  new(model.Usage),
+	}
+
+	for i, input := range inputs {
+
+		request.Input = []model.EmbeddingInput{input}
+
+		data, err := v.ConvTextEmbeddingsRequestOfficial(ctx, request)
+		if err != nil {
+			logger.Errorf(ctx, "TextEmbeddings VolcEngine ConvTextEmbeddingsRequestOfficial error: %v", err)
+			return response, err
+		}
+
+		bytes, responseHeader, err := v.embeddings(ctx, data, true)
+		if err != nil {
+			logger.Errorf(ctx, "TextEmbeddings VolcEngine model: %s, input index: %d, error: %v", v.Model, i, err)
+			return response, err
+		}
+
+		embeddingRes, err := v.ConvTextEmbeddingsResponse(ctx, bytes)
+		if err != nil {
+			logger.Errorf(ctx, "TextEmbeddings VolcEngine ConvTextEmbeddingsResponse error: %v", err)
+			return response, err
+		}
+
+		for _, embedding := range embeddingRes.Data {
+			if value, ok := embedding.(map[string]any); ok {
+				value["index"] = i
+			}
+			response.Data = append(response.Data, embedding)
+		}
+
+		response.Model = embeddingRes.Model
+		response.ResponseHeaders = responseHeader
+
+		if embeddingRes.Usage != nil {
+			response.Usage.PromptTokens += embeddingRes.Usage.PromptTokens
+			response.Usage.TotalTokens += embeddingRes.Usage.TotalTokens
+			response.Usage.PromptTokensDetails.TextTokens += embeddingRes.Usage.PromptTokensDetails.TextTokens
+			response.Usage.PromptTokensDetails.ImageTokens += embeddingRes.Usage.PromptTokensDetails.ImageTokens
+		}
+	}
+
+	logger.Infof(ctx, "TextEmbeddings VolcEngine model: %s finished, inputs: %d", v.Model, len(inputs))
+
+	return response, nil
+}
+
+func (v *VolcEngine) embeddings(ctx context.Context, data []byte, isMultimodal bool) ([]byte, http.Header, error) {
+
+	path := v.Path
+	if path == "" {
+		if isMultimodal {
+			path = "/embeddings/multimodal"
+		} else {
+			path = "/embeddings"
+		}
+	}
+
+	return util.HttpPost(ctx, v.BaseUrl+path, v.header, data, nil, v.Timeout, v.ProxyUrl, v.requestErrorHandler)
 }
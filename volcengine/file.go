@@ -3,30 +3,27 @@ package volcengine
 import (
 	"context"
 
+	"github.com/iimeta/fastapi-sdk/v2/errors"
 	"github.com/iimeta/fastapi-sdk/v2/model"
 )
 
+// FileUpload 火山引擎没有文件接口, 批量推理的输入文件需上传至TOS, 以 tos://bucket/key 作为input_file_id
 func (v *VolcEngine) FileUpload(ctx context.Context, request model.FileUploadRequest) (response model.FileResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("VolcEngine does not support uploading files, please upload batch input files to TOS and use tos://bucket/key as input_file_id")
 }
 
 func (v *VolcEngine) FileList(ctx context.Context, request model.FileListRequest) (response model.FileListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("VolcEngine does not support listing files")
 }
 
 func (v *VolcEngine) FileRetrieve(ctx context.Context, request model.FileRetrieveRequest) (response model.FileResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("VolcEngine does not support retrieving files")
 }
 
 func (v *VolcEngine) FileDelete(ctx context.Context, request model.FileDeleteRequest) (response model.FileResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("VolcEngine does not support deleting files")
 }
 
 func (v *VolcEngine) FileContent(ctx context.Context, request model.FileContentRequest) (response model.FileContentResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("VolcEngine does not support downloading file content, please download batch output files from TOS")
 }
@@ -2,26 +2,321 @@ package volcengine
 
 import (
 	"context"
+	"io"
+	"slices"
 
+	"github.com/gogf/gf/v2/os/grpool"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (v *VolcEngine) ImageGenerations(ctx context.Context, data []byte) (response model.ImageResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "ImageGenerations VolcEngine model: %s start", v.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "ImageGenerations VolcEngine model: %s totalTime: %d ms", v.Model, response.TotalTime)
+	}()
+
+	if !slices.Contains(v.ReqPassthroughParams, "req_data") {
+
+		request, err := v.ConvImageGenerationsRequest(ctx, data)
+		if err != nil {
+			logger.Errorf(ctx, "ImageGenerations VolcEngine ConvImageGenerationsRequest error: %v", err)
+			return response, err
+		}
+
+		request.Stream = false
+
+		if data, err = v.ConvImageGenerationsRequestOfficial(ctx, request); err != nil {
+			logger.Errorf(ctx, "ImageGenerations VolcEngine ConvImageGenerationsRequestOfficial error: %v", err)
+			return response, err
+		}
+	}
+
+	if v.Path == "" {
+		v.Path = "/images/generations"
+	}
+
+	bytes, responseHeader, err := util.HttpPost(ctx, v.BaseUrl+v.Path, v.header, data, nil, v.Timeout, v.ProxyUrl, v.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "ImageGenerations VolcEngine model: %s, error: %v", v.Model, err)
+		return response, err
+	}
+
+	if response, err = v.ConvImageGenerationsResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "ImageGenerations VolcEngine ConvImageGenerationsResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseBytes = bytes
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "ImageGenerations VolcEngine model: %s finished", v.Model)
+
+	return response, nil
 }
 
 func (v *VolcEngine) ImageEdits(ctx context.Context, request model.ImageEditRequest) (response model.ImageResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "ImageEdits VolcEngine model: %s start", v.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "ImageEdits VolcEngine model: %s totalTime: %d ms", v.Model, response.TotalTime)
+	}()
+
+	request.Stream = false
+
+	data, err := v.ConvImageEditsRequestOfficial(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "ImageEdits VolcEngine ConvImageEditsRequestOfficial error: %v", err)
+		return response, err
+	}
+
+	if v.Path == "" {
+		v.Path = "/images/generations"
+	}
+
+	bytes, responseHeader, err := util.HttpPost(ctx, v.BaseUrl+v.Path, v.header, data, nil, v.Timeout, v.ProxyUrl, v.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "ImageEdits VolcEngine model: %s, error: %v", v.Model, err)
+		return response, err
+	}
+
+	if response, err = v.ConvImageEditsResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "ImageEdits VolcEngine ConvImageEditsResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseBytes = bytes
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "ImageEdits VolcEngine model: %s finished", v.Model)
+
+	return response, nil
 }
 
 func (v *VolcEngine) ImageGenerationsStream(ctx context.Context, data []byte) (responseChan chan *model.ImageResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "ImageGenerationsStream VolcEngine model: %s start", v.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		if err != nil {
+			logger.Infof(ctx, "ImageGenerationsStream VolcEngine model: %s totalTime: %d ms", v.Model, gtime.TimestampMilli()-now)
+		}
+	}()
+
+	if !slices.Contains(v.ReqPassthroughParams, "req_data") {
+
+		request, err := v.ConvImageGenerationsRequest(ctx, data)
+		if err != nil {
+			logger.Errorf(ctx, "ImageGenerationsStream VolcEngine ConvImageGenerationsRequest error: %v", err)
+			return responseChan, err
+		}
+
+		request.Stream = true
+
+		if data, err = v.ConvImageGenerationsRequestOfficial(ctx, request); err != nil {
+			logger.Errorf(ctx, "ImageGenerationsStream VolcEngine ConvImageGenerationsRequestOfficial error: %v", err)
+			return responseChan, err
+		}
+	}
+
+	if v.Path == "" {
+		v.Path = "/images/generations"
+	}
+
+	stream, err := util.SSEClient(ctx, v.BaseUrl+v.Path, v.header, data, v.Timeout, v.ProxyUrl, v.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "ImageGenerationsStream VolcEngine model: %s, error: %v", v.Model, err)
+		return responseChan, err
+	}
+
+	streamResponseHeaders := stream.Response.Header
+
+	duration := gtime.TimestampMilli()
+
+	responseChan = make(chan *model.ImageResponse)
+
+	if err = grpool.AddWithRecover(ctx, func(ctx context.Context) {
+
+		defer func() {
+			if err := stream.Close(); err != nil {
+				logger.Errorf(ctx, "ImageGenerationsStream VolcEngine model: %s, stream.Close error: %v", v.Model, err)
+			}
+
+			end := gtime.TimestampMilli()
+			logger.Infof(ctx, "ImageGenerationsStream VolcEngine model: %s connTime: %d ms, duration: %d ms, totalTime: %d ms", v.Model, duration-now, end-duration, end-now)
+		}()
+
+		for {
+
+			responseBytes, err := stream.Recv()
+			if err != nil {
+
+				if errors.Is(err, io.EOF) {
+					logger.Infof(ctx, "ImageGenerationsStream VolcEngine model: %s finished", v.Model)
+				} else {
+					logger.Errorf(ctx, "ImageGenerationsStream VolcEngine model: %s, error: %v", v.Model, err)
+				}
+
+				end := gtime.TimestampMilli()
+				responseChan <- &model.ImageResponse{
+					ConnTime:  duration - now,
+					Duration:  end - duration,
+					TotalTime: end - now,
+					Error:     err,
+				}
+
+				return
+			}
+
+			response, err := v.ConvImageGenerationsStreamResponse(ctx, responseBytes)
+			if err != nil {
+				logger.Errorf(ctx, "ImageGenerationsStream VolcEngine ConvImageGenerationsStreamResponse error: %v", err)
+
+				end := gtime.TimestampMilli()
+				responseChan <- &model.ImageResponse{
+					ConnTime:  duration - now,
+					Duration:  end - duration,
+					TotalTime: end - now,
+					Error:     err,
+				}
+
+				return
+			}
+
+			end := gtime.TimestampMilli()
+
+			response.ConnTime = duration - now
+			response.Duration = end - duration
+			response.TotalTime = end - now
+			response.ResponseHeaders = streamResponseHeaders
+
+			if event := stream.Event(); event != "" {
+				response.Event = event
+			}
+
+			responseChan <- &response
+		}
+
+	}, nil); err != nil {
+		logger.Errorf(ctx, "ImageGenerationsStream VolcEngine model: %s, error: %v", v.Model, err)
+		return responseChan, err
+	}
+
+	return responseChan, nil
 }
 
 func (v *VolcEngine) ImageEditsStream(ctx context.Context, request model.ImageEditRequest) (responseChan chan *model.ImageResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "ImageEditsStream VolcEngine model: %s start", v.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		if err != nil {
+			logger.Infof(ctx, "ImageEditsStream VolcEngine model: %s totalTime: %d ms", v.Model, gtime.TimestampMilli()-now)
+		}
+	}()
+
+	request.Stream = true
+
+	data, err := v.ConvImageEditsRequestOfficial(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "ImageEditsStream VolcEngine ConvImageEditsRequestOfficial error: %v", err)
+		return responseChan, err
+	}
+
+	if v.Path == "" {
+		v.Path = "/images/generations"
+	}
+
+	stream, err := util.SSEClient(ctx, v.BaseUrl+v.Path, v.header, data, v.Timeout, v.ProxyUrl, v.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "ImageEditsStream VolcEngine model: %s, error: %v", v.Model, err)
+		return responseChan, err
+	}
+
+	streamResponseHeaders := stream.Response.Header
+
+	duration := gtime.TimestampMilli()
+
+	responseChan = make(chan *model.ImageResponse)
+
+	if err = grpool.AddWithRecover(ctx, func(ctx context.Context) {
+
+		defer func() {
+			if err := stream.Close(); err != nil {
+				logger.Errorf(ctx, "ImageEditsStream VolcEngine model: %s, stream.Close error: %v", v.Model, err)
+			}
+
+			end := gtime.TimestampMilli()
+			logger.Infof(ctx, "ImageEditsStream VolcEngine model: %s connTime: %d ms, duration: %d ms, totalTime: %d ms", v.Model, duration-now, end-duration, end-now)
+		}()
+
+		for {
+
+			responseBytes, err := stream.Recv()
+			if err != nil {
+
+				if errors.Is(err, io.EOF) {
+					logger.Infof(ctx, "ImageEditsStream VolcEngine model: %s finished", v.Model)
+				} else {
+					logger.Errorf(ctx, "ImageEditsStream VolcEngine model: %s, error: %v", v.Model, err)
+				}
+
+				end := gtime.TimestampMilli()
+				responseChan <- &model.ImageResponse{
+					ConnTime:  duration - now,
+					Duration:  end - duration,
+					TotalTime: end - now,
+					Error:     err,
+				}
+
+				return
+			}
+
+			response, err := v.ConvImageGenerationsStreamResponse(ctx, responseBytes)
+			if err != nil {
+				logger.Errorf(ctx, "ImageEditsStream VolcEngine ConvImageGenerationsStreamResponse error: %v", err)
+
+				end := gtime.TimestampMilli()
+				responseChan <- &model.ImageResponse{
+					ConnTime:  duration - now,
+					Duration:  end - duration,
+					TotalTime: end - now,
+					Error:     err,
+				}
+
+				return
+			}
+
+			end := gtime.TimestampMilli()
+
+			response.ConnTime = duration - now
+			response.Duration = end - duration
+			response.TotalTime = end - now
+			response.ResponseHeaders = streamResponseHeaders
+
+			if event := stream.Event(); event != "" {
+				response.Event = event
+			}
+
+			responseChan <- &response
+		}
+
+	}, nil); err != nil {
+		logger.Errorf(ctx, "ImageEditsStream VolcEngine model: %s, error: %v", v.Model, err)
+		return responseChan, err
+	}
+
+	return responseChan, nil
 }
@@ -0,0 +1,52 @@
+package volcengine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/iimeta/fastapi-sdk/v2/util"
+)
+
+// 火山引擎OpenAPI签名, 与AWS SigV4类似, 签名算法为HMAC-SHA256, 凭证范围以request结尾
+func signHeader(host string, query url.Values, region, service, accessKey, secretKey string, data []byte) map[string]string {
+
+	now := time.Now().UTC()
+	xDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(data)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+
+	header := map[string]string{
+		"Content-Type":     "application/json",
+		"Host":             host,
+		"X-Date":           xDate,
+		"X-Content-Sha256": payloadHashHex,
+	}
+
+	signedHeaders := "content-type;host;x-content-sha256;x-date"
+
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-content-sha256:%s\nx-date:%s\n", header["Content-Type"], host, payloadHashHex, xDate)
+
+	// url.Values.Encode按key排序, 满足规范化查询字符串的要求
+	canonicalRequest := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s", http.MethodPost, "/", query.Encode(), canonicalHeaders, signedHeaders, payloadHashHex)
+
+	credentialScope := fmt.Sprintf("%s/%s/%s/request", shortDate, region, service)
+
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := fmt.Sprintf("HMAC-SHA256\n%s\n%s\n%s", xDate, credentialScope, hex.EncodeToString(canonicalRequestHash[:]))
+
+	dateKey := util.HMACSHA256([]byte(secretKey), []byte(shortDate))
+	regionKey := util.HMACSHA256(dateKey, []byte(region))
+	serviceKey := util.HMACSHA256(regionKey, []byte(service))
+	signingKey := util.HMACSHA256(serviceKey, []byte("request"))
+	signature := hex.EncodeToString(util.HMACSHA256(signingKey, []byte(stringToSign)))
+
+	header["Authorization"] = fmt.Sprintf("HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, credentialScope, signedHeaders, signature)
+
+	return header
+}
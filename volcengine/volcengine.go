@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 
 	"github.com/iimeta/fastapi-sdk/v2/errors"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/options"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 type VolcEngine struct {
@@ -42,6 +44,35 @@ func NewAdapter(ctx context.Context, options *options.AdapterOptions) *VolcEngin
 	return volcengine
 }
 
+// 批量推理等管控面接口使用OpenAPI, 需使用AccessKey/SecretKey签名, Key为方舟的API Key, 仅用于数据面接口
+func (v *VolcEngine) openApi(ctx context.Context, action string, data []byte) ([]byte, error) {
+
+	if v.AccessKey == "" || v.SecretKey == "" {
+		return nil, errors.New("VolcEngine OpenAPI requires AccessKey and SecretKey in adapter options")
+	}
+
+	region := v.Region
+	if region == "" {
+		region = "cn-beijing"
+	}
+
+	host := fmt.Sprintf("ark.%s.volcengineapi.com", region)
+
+	query := url.Values{}
+	query.Set("Action", action)
+	query.Set("Version", "2024-01-01")
+
+	header := signHeader(host, query, region, "ark", v.AccessKey, v.SecretKey, data)
+
+	bytes, _, err := util.HttpPost(ctx, fmt.Sprintf("https://%s/?%s", host, query.Encode()), header, data, nil, v.Timeout, v.ProxyUrl, v.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "OpenAPI VolcEngine action: %s, error: %v", action, err)
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
 func (v *VolcEngine) requestErrorHandler(ctx context.Context, response *http.Response) (err error) {
 	bytes, err := io.ReadAll(response.Body)
 	if err != nil {
@@ -1,17 +1,666 @@
 package xfyun
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/gogf/gf/v2/encoding/gbase64"
+	"github.com/gogf/gf/v2/os/grpool"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/gorilla/websocket"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
+)
+
+const (
+	audioFrameSize     = 1280                  // 每帧音频大小, 16k采样率16bit单声道下为40ms
+	audioFrameInterval = 40 * time.Millisecond // 发送间隔, 讯飞要求按实际时长匀速发送
 )
 
 func (x *Xfyun) AudioSpeech(ctx context.Context, data []byte) (response model.SpeechResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "AudioSpeech Xfyun model: %s start", x.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "AudioSpeech Xfyun model: %s totalTime: %d ms", x.Model, response.TotalTime)
+	}()
+
+	// req_data透传时为讯飞原生请求, 音频原样返回
+	responseFormat := ""
+
+	if !slices.Contains(x.ReqPassthroughParams, "req_data") {
+
+		request, err := x.ConvAudioSpeechRequest(ctx, data)
+		if err != nil {
+			logger.Errorf(ctx, "AudioSpeech Xfyun ConvAudioSpeechRequest error: %v", err)
+			return response, err
+		}
+
+		// 讯飞仅返回PCM或MP3音频, PCM可封装为WAV
+		if request.ResponseFormat != "" && request.ResponseFormat != "mp3" && request.ResponseFormat != "wav" && request.ResponseFormat != "pcm" {
+			return response, fmt.Errorf("AudioSpeech Xfyun model: %s, unsupported response_format: %s, only mp3, wav and pcm are supported", x.Model, request.ResponseFormat)
+		}
+
+		responseFormat = request.ResponseFormat
+
+		if data, err = x.ConvAudioSpeechRequestOfficial(ctx, request); err != nil {
+			logger.Errorf(ctx, "AudioSpeech Xfyun ConvAudioSpeechRequestOfficial error: %v", err)
+			return response, err
+		}
+	}
+
+	rawURL := x.customUrl
+	if rawURL == "" {
+		rawURL = "wss://tts-api.xfyun.cn/v2/tts"
+	}
+
+	conn, err := util.WebSocketClient(ctx, x.getAudioUrl(ctx, rawURL), nil, websocket.TextMessage, data, x.ProxyUrl)
+	if err != nil {
+		logger.Errorf(ctx, "AudioSpeech Xfyun model: %s, error: %v", x.Model, err)
+		return response, err
+	}
+
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.Errorf(ctx, "AudioSpeech Xfyun model: %s, conn.Close error: %v", x.Model, err)
+		}
+	}()
+
+	audio := new(bytes.Buffer)
+
+	for {
+
+		_, message, err := conn.ReadMessage(ctx)
+		if err != nil {
+			logger.Errorf(ctx, "AudioSpeech Xfyun model: %s, error: %v", x.Model, err)
+			return response, err
+		}
+
+		ttsRes := model.XfyunTtsRes{}
+		if err = json.Unmarshal(message, &ttsRes); err != nil {
+			logger.Errorf(ctx, "AudioSpeech Xfyun model: %s, message: %s, error: %v", x.Model, message, err)
+			return response, errors.New(fmt.Sprintf("message: %s, error: %v", message, err))
+		}
+
+		if ttsRes.Code != 0 {
+			err = x.audioApiErrorHandler(ttsRes.Code, message)
+			logger.Errorf(ctx, "AudioSpeech Xfyun model: %s, error: %v", x.Model, err)
+			return response, err
+		}
+
+		if ttsRes.Data == nil {
+			continue
+		}
+
+		if ttsRes.Data.Audio != "" {
+
+			frame, err := gbase64.DecodeString(ttsRes.Data.Audio)
+			if err != nil {
+				logger.Errorf(ctx, "AudioSpeech Xfyun model: %s, error: %v", x.Model, err)
+				return response, err
+			}
+
+			audio.Write(frame)
+		}
+
+		if ttsRes.Data.Status == 2 {
+			break
+		}
+	}
+
+	if response, err = x.convAudioSpeechResponse(ctx, audio.Bytes(), responseFormat); err != nil {
+		logger.Errorf(ctx, "AudioSpeech Xfyun ConvAudioSpeechResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "AudioSpeech Xfyun model: %s finished", x.Model)
+
+	return response, nil
 }
 
+// AudioTranscriptions 模型名称包含rtasr时使用实时语音转写, 支持长音频, 否则使用语音听写, 音频时长最长60秒
 func (x *Xfyun) AudioTranscriptions(ctx context.Context, request model.AudioRequest) (response model.AudioResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "AudioTranscriptions Xfyun model: %s start", x.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "AudioTranscriptions Xfyun model: %s totalTime: %d ms", x.Model, response.TotalTime)
+	}()
+
+	audio, encoding, err := readAudio(request)
+	if err != nil {
+		logger.Errorf(ctx, "AudioTranscriptions Xfyun model: %s, error: %v", x.Model, err)
+		return response, err
+	}
+
+	duration, err := audioDuration(audio, encoding)
+	if err != nil {
+		logger.Errorf(ctx, "AudioTranscriptions Xfyun model: %s, error: %v", x.Model, err)
+		return response, err
+	}
+
+	var segments []model.Segment
+
+	if strings.Contains(x.Model, "rtasr") {
+
+		if encoding != "raw" {
+			return response, fmt.Errorf("AudioTranscriptions Xfyun model: %s, only wav and pcm are supported", x.Model)
+		}
+
+		segments, err = x.audioTranscriptionsRtasr(ctx, request, audio)
+
+	} else {
+		segments, err = x.audioTranscriptionsIat(ctx, request, audio, encoding, duration)
+	}
+
+	if err != nil {
+		logger.Errorf(ctx, "AudioTranscriptions Xfyun model: %s, error: %v", x.Model, err)
+		return response, err
+	}
+
+	texts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		texts = append(texts, segment.Text)
+	}
+
+	response = model.AudioResponse{
+		Task:     "transcribe",
+		Language: request.Language,
+		Text:     strings.Join(texts, ""),
+		Duration: duration,
+	}
+
+	if request.ResponseFormat == "verbose_json" {
+		response.Segments = segments
+	}
+
+	response.Usage.Type = "duration"
+	response.Usage.Seconds = int(math.Ceil(response.Duration))
+
+	logger.Infof(ctx, "AudioTranscriptions Xfyun model: %s finished", x.Model)
+
+	return response, nil
+}
+
+// 语音听写, 按帧发送音频, 每个结果片段对应一个segment
+func (x *Xfyun) audioTranscriptionsIat(ctx context.Context, request model.AudioRequest, audio []byte, encoding string, duration float64) ([]model.Segment, error) {
+
+	rawURL := x.customUrl
+	if rawURL == "" {
+		rawURL = "wss://iat-api.xfyun.cn/v2/iat"
+	}
+
+	conn, err := util.WebSocketClient(ctx, x.getAudioUrl(ctx, rawURL), nil, 0, nil, x.ProxyUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.Errorf(ctx, "AudioTranscriptions Xfyun model: %s, conn.Close error: %v", x.Model, err)
+		}
+	}()
+
+	language := "zh_cn"
+	if strings.HasPrefix(request.Language, "en") {
+		language = "en_us"
+	}
+
+	if err = grpool.AddWithRecover(ctx, func(ctx context.Context) {
+
+		for i := 0; ; i += audioFrameSize {
+
+			iatReq := model.XfyunIatReq{
+				Data: model.XfyunIatData{
+					Status:   1,
+					Format:   "audio/L16;rate=16000",
+					Encoding: encoding,
+				},
+			}
+
+			if i == 0 {
+
+				iatReq.Common = &model.XfyunCommon{
+					AppId: x.appId,
+				}
+
+				iatReq.Business = &model.XfyunIatBusiness{
+					Language: language,
+					Domain:   "iat",
+					Accent:   "mandarin",
+					VadEos:   10000,
+				}
+
+				iatReq.Data.Status = 0
+			}
+
+			if i >= len(audio) {
+				iatReq.Data.Status = 2
+			} else {
+				iatReq.Data.Audio = gbase64.EncodeToString(audio[i:min(i+audioFrameSize, len(audio))])
+			}
+
+			if err := conn.WriteJSON(ctx, iatReq); err != nil {
+				logger.Errorf(ctx, "AudioTranscriptions Xfyun model: %s, error: %v", x.Model, err)
+				return
+			}
+
+			if iatReq.Data.Status == 2 {
+				return
+			}
+
+			time.Sleep(audioFrameInterval)
+		}
+
+	}, nil); err != nil {
+		return nil, err
+	}
+
+	results := make(map[int]model.Segment)
+
+	for {
+
+		_, message, err := conn.ReadMessage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		iatRes := model.XfyunIatRes{}
+		if err = json.Unmarshal(message, &iatRes); err != nil {
+			return nil, errors.New(fmt.Sprintf("message: %s, error: %v", message, err))
+		}
+
+		if iatRes.Code != 0 {
+			return nil, x.audioApiErrorHandler(iatRes.Code, message)
+		}
+
+		if iatRes.Data == nil {
+			continue
+		}
+
+		if iatRes.Data.Result != nil && len(iatRes.Data.Result.Ws) > 0 {
+
+			text := ""
+			for _, ws := range iatRes.Data.Result.Ws {
+				if len(ws.Cw) > 0 {
+					text += ws.Cw[0].W
+				}
+			}
+
+			if text != "" {
+				results[iatRes.Data.Result.Sn] = model.Segment{
+					Start: float64(iatRes.Data.Result.Ws[0].Bg) / 100,
+					Text:  text,
+				}
+			}
+		}
+
+		if iatRes.Data.Status == 2 {
+			break
+		}
+	}
+
+	sns := make([]int, 0, len(results))
+	for sn := range results {
+		sns = append(sns, sn)
+	}
+
+	sort.Ints(sns)
+
+	segments := make([]model.Segment, 0, len(sns))
+	for i, sn := range sns {
+
+		segment := results[sn]
+		segment.Id = i
+
+		// 听写结果只返回起始帧, 以下一片段的开始作为结束时间, 最后一个片段以音频时长结束
+		if i+1 < len(sns) {
+			segment.End = results[sns[i+1]].Start
+		} else {
+			segment.End = max(duration, segment.Start)
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+// 实时语音转写, 直接发送二进制音频, 发送结束标识后等待服务端返回全部最终结果
+func (x *Xfyun) audioTranscriptionsRtasr(ctx context.Context, request model.AudioRequest, audio []byte) ([]model.Segment, error) {
+
+	rawURL := x.customUrl
+	if rawURL == "" {
+		rawURL = "wss://rtasr.xfyun.cn/v1/ws"
+	}
+
+	lang := "cn"
+	if strings.HasPrefix(request.Language, "en") {
+		lang = "en"
+	}
+
+	conn, err := util.WebSocketClient(ctx, x.getRtasrUrl(ctx, rawURL, lang), nil, 0, nil, x.ProxyUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.Errorf(ctx, "AudioTranscriptions Xfyun model: %s, conn.Close error: %v", x.Model, err)
+		}
+	}()
+
+	started := make(chan struct{})
+
+	if err = grpool.AddWithRecover(ctx, func(ctx context.Context) {
+
+		// 握手成功后才能发送音频
+		<-started
+
+		for i := 0; i < len(audio); i += audioFrameSize {
+
+			if err := conn.WriteMessage(ctx, websocket.BinaryMessage, audio[i:min(i+audioFrameSize, len(audio))]); err != nil {
+				logger.Errorf(ctx, "AudioTranscriptions Xfyun model: %s, error: %v", x.Model, err)
+				return
+			}
+
+			time.Sleep(audioFrameInterval)
+		}
+
+		if err := conn.WriteMessage(ctx, websocket.BinaryMessage, []byte(`{"end": true}`)); err != nil {
+			logger.Errorf(ctx, "AudioTranscriptions Xfyun model: %s, error: %v", x.Model, err)
+		}
+
+	}, nil); err != nil {
+		close(started)
+		return nil, err
+	}
+
+	var (
+		segments  []model.Segment
+		isStarted = false
+	)
+
+	defer func() {
+		if !isStarted {
+			close(started)
+		}
+	}()
+
+	for {
+
+		_, message, err := conn.ReadMessage(ctx)
+		if err != nil {
+
+			// 全部结果返回后由服务端关闭连接
+			if util.IsNormalClose(err) || errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		rtasrRes := model.XfyunRtasrRes{}
+		if err = json.Unmarshal(message, &rtasrRes); err != nil {
+			return nil, errors.New(fmt.Sprintf("message: %s, error: %v", message, err))
+		}
+
+		if rtasrRes.Code != "0" || rtasrRes.Action == "error" {
+			return nil, x.audioApiErrorHandler(gconv.Int(rtasrRes.Code), message)
+		}
+
+		switch rtasrRes.Action {
+		case "started":
+			if !isStarted {
+				isStarted = true
+				close(started)
+			}
+		case "result":
+
+			rtasrData := model.XfyunRtasrData{}
+			if err = json.Unmarshal([]byte(rtasrRes.Data), &rtasrData); err != nil {
+				return nil, errors.New(fmt.Sprintf("data: %s, error: %v", rtasrRes.Data, err))
+			}
+
+			// 只保留最终结果
+			if rtasrData.Cn.St.Type != "0" {
+				continue
+			}
+
+			text := ""
+			for _, rt := range rtasrData.Cn.St.Rt {
+				for _, ws := range rt.Ws {
+					if len(ws.Cw) > 0 {
+						text += ws.Cw[0].W
+					}
+				}
+			}
+
+			if text == "" {
+				continue
+			}
+
+			segments = append(segments, model.Segment{
+				Id:    len(segments),
+				Start: gconv.Float64(rtasrData.Cn.St.Bg) / 1000,
+				End:   gconv.Float64(rtasrData.Cn.St.Ed) / 1000,
+				Text:  text,
+			})
+		}
+	}
+
+	return segments, nil
+}
+
+// 读取音频文件, 仅支持16k采样率16bit单声道的wav/pcm以及mp3
+func readAudio(request model.AudioRequest) (audio []byte, encoding string, err error) {
+
+	if request.File == nil {
+		return nil, "", errors.New("file is required")
+	}
+
+	file, err := request.File.Open()
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if audio, err = io.ReadAll(file); err != nil {
+		return nil, "", err
+	}
+
+	switch strings.ToLower(filepath.Ext(request.File.Filename)) {
+	case ".wav":
+		if audio, err = wavToPcm(audio); err != nil {
+			return nil, "", err
+		}
+		return audio, "raw", nil
+	case ".pcm":
+		return audio, "raw", nil
+	case ".mp3":
+		return audio, "lame", nil
+	}
+
+	return nil, "", fmt.Errorf("unsupported audio format: %s, only wav, pcm and mp3 are supported", request.File.Filename)
+}
+
+// 去除WAV文件头, 校验fmt块为16k采样率16bit单声道PCM后返回data块
+func wavToPcm(wav []byte) ([]byte, error) {
+
+	if len(wav) < 12 || string(wav[:4]) != "RIFF" || string(wav[8:12]) != "WAVE" {
+		return nil, errors.New("invalid wav file")
+	}
+
+	isFmtChecked := false
+
+	for i := 12; i+8 <= len(wav); {
+
+		id := string(wav[i : i+4])
+		size := int(binary.LittleEndian.Uint32(wav[i+4 : i+8]))
+
+		switch id {
+		case "fmt ":
+
+			if size < 16 || i+8+16 > len(wav) {
+				return nil, errors.New("invalid wav fmt chunk")
+			}
+
+			chunk := wav[i+8:]
+			audioFormat := binary.LittleEndian.Uint16(chunk[0:2])
+			channels := binary.LittleEndian.Uint16(chunk[2:4])
+			sampleRate := binary.LittleEndian.Uint32(chunk[4:8])
+			bitsPerSample := binary.LittleEndian.Uint16(chunk[14:16])
+
+			// 1为PCM, 0xFFFE为WAVE_FORMAT_EXTENSIBLE
+			if (audioFormat != 1 && audioFormat != 0xFFFE) || channels != 1 || sampleRate != 16000 || bitsPerSample != 16 {
+				return nil, fmt.Errorf("unsupported wav format: format %d, %d channels, %d Hz, %d bit, only 16000 Hz 16 bit mono pcm is supported", audioFormat, channels, sampleRate, bitsPerSample)
+			}
+
+			isFmtChecked = true
+
+		case "data":
+
+			if !isFmtChecked {
+				return nil, errors.New("invalid wav file: fmt chunk not found before data chunk")
+			}
+
+			return wav[i+8 : min(i+8+size, len(wav))], nil
+		}
+
+		i += 8 + size + size%2
+	}
+
+	return nil, errors.New("invalid wav file: data chunk not found")
+}
+
+// 计算音频时长(秒), PCM为16k采样率16bit单声道即每秒32000字节, MP3累加各帧时长
+func audioDuration(audio []byte, encoding string) (float64, error) {
+
+	if encoding == "raw" {
+		return float64(len(audio)) / 32000, nil
+	}
+
+	duration := mp3Duration(audio)
+	if duration == 0 {
+		return 0, errors.New("invalid mp3 file: no mpeg audio frame found")
+	}
+
+	return duration, nil
+}
+
+var (
+	// MPEG-1与MPEG-2/2.5各层的比特率(kbps), 按[version][layer][index]索引
+	mp3Bitrates = [2][3][16]int{
+		{
+			{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0},
+			{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},
+			{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+		},
+		{
+			{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+			{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+			{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+		},
+	}
+	// MPEG-1的采样率, MPEG-2减半, MPEG-2.5再减半
+	mp3SampleRates = [3]int{44100, 48000, 32000}
+)
+
+// 解析MPEG音频帧头累加时长, 兼容VBR, 跳过ID3v2标签与无法识别的数据
+func mp3Duration(data []byte) float64 {
+
+	i := 0
+
+	if len(data) >= 10 && string(data[:3]) == "ID3" {
+		i = 10 + (int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F))
+		if data[5]&0x10 != 0 {
+			i += 10
+		}
+	}
+
+	duration := 0.0
+
+	for i+4 <= len(data) {
+
+		if data[i] != 0xFF || data[i+1]&0xE0 != 0xE0 {
+			i++
+			continue
+		}
+
+		version := (data[i+1] >> 3) & 0x03 // 0: MPEG-2.5, 2: MPEG-2, 3: MPEG-1
+		layer := (data[i+1] >> 1) & 0x03   // 1: Layer III, 2: Layer II, 3: Layer I
+		bitrateIndex := data[i+2] >> 4
+		sampleRateIndex := (data[i+2] >> 2) & 0x03
+		padding := int((data[i+2] >> 1) & 0x01)
+
+		if version == 1 || layer == 0 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+			i++
+			continue
+		}
+
+		versionIndex := 0
+		sampleRate := mp3SampleRates[sampleRateIndex]
+
+		switch version {
+		case 2:
+			versionIndex = 1
+			sampleRate /= 2
+		case 0:
+			versionIndex = 1
+			sampleRate /= 4
+		}
+
+		bitrate := mp3Bitrates[versionIndex][3-layer][bitrateIndex] * 1000
+
+		var samples, frameSize int
+
+		switch layer {
+		case 3:
+			samples = 384
+			frameSize = (12*bitrate/sampleRate + padding) * 4
+		case 2:
+			samples = 1152
+			frameSize = 144*bitrate/sampleRate + padding
+		default:
+			samples = 1152
+			if versionIndex == 1 {
+				samples = 576
+			}
+			frameSize = samples/8*bitrate/sampleRate + padding
+		}
+
+		if frameSize <= 4 {
+			i++
+			continue
+		}
+
+		duration += float64(samples) / float64(sampleRate)
+		i += frameSize
+	}
+
+	return duration
+}
+
+func (x *Xfyun) audioApiErrorHandler(code int, message []byte) error {
+	return errors.NewApiError(500, code, string(message), "api_error", "")
 }
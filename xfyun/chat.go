@@ -28,6 +28,9 @@ func (x *Xfyun) ChatCompletions(ctx context.Context, data any) (response model.C
 		logger.Infof(ctx, "ChatCompletions Xfyun model: %s connTime: %d ms, duration: %d ms, totalTime: %d ms", x.Model, response.ConnTime, response.Duration, response.TotalTime)
 	}()
 
+	// req_data透传时为星火原生请求, 函数调用以function_call格式返回
+	isToolCall := false
+
 	if !slices.Contains(x.ReqPassthroughParams, "req_data") {
 
 		request, err := x.ConvChatCompletionsRequest(ctx, data)
@@ -36,12 +39,20 @@ func (x *Xfyun) ChatCompletions(ctx context.Context, data any) (response model.C
 			return response, err
 		}
 
-		if data, err = x.ConvChatCompletionsRequestOfficial(ctx, request); err != nil {
+		isToolCall = isToolCallRequest(request)
+
+		if x.isHttp {
+			data = request
+		} else if data, err = x.ConvChatCompletionsRequestOfficial(ctx, request); err != nil {
 			logger.Errorf(ctx, "ChatCompletions Xfyun ConvChatCompletionsRequestOfficial error: %v", err)
 			return response, err
 		}
 	}
 
+	if x.isHttp {
+		return x.chatCompletionsHttp(ctx, data)
+	}
+
 	conn, err := util.WebSocketClient(ctx, x.getWebSocketUrl(ctx), nil, websocket.TextMessage, gjson.MustEncode(data), x.ProxyUrl)
 	if err != nil {
 		logger.Errorf(ctx, "ChatCompletions Xfyun model: %s, error: %v", x.Model, err)
@@ -57,6 +68,8 @@ func (x *Xfyun) ChatCompletions(ctx context.Context, data any) (response model.C
 	var (
 		duration          = gtime.TimestampMilli()
 		responseContent   = ""
+		reasoningContent  = ""
+		functionCall      *model.FunctionCall
 		chatCompletionRes = model.XfyunChatCompletionRes{}
 	)
 
@@ -83,24 +96,40 @@ func (x *Xfyun) ChatCompletions(ctx context.Context, data any) (response model.C
 		}
 
 		responseContent += chatCompletionRes.Payload.Choices.Text[0].Content
+		reasoningContent += chatCompletionRes.Payload.Choices.Text[0].ReasoningContent
+
+		if chatCompletionRes.Payload.Choices.Text[0].FunctionCall != nil {
+			functionCall = chatCompletionRes.Payload.Choices.Text[0].FunctionCall
+		}
 
 		if chatCompletionRes.Header.Status == 2 {
 			break
 		}
 	}
 
+	message := &model.ChatCompletionMessage{
+		Role:             chatCompletionRes.Payload.Choices.Text[0].Role,
+		Content:          responseContent,
+		ReasoningContent: reasoningContent,
+	}
+
+	if functionCall != nil {
+		if isToolCall {
+			message.ToolCalls = x.convToolCalls(functionCall, false)
+		} else {
+			message.FunctionCall = functionCall
+		}
+	}
+
 	response = model.ChatCompletionResponse{
 		Id:      consts.COMPLETION_ID_PREFIX + chatCompletionRes.Header.Sid,
 		Object:  consts.COMPLETION_OBJECT,
 		Created: gtime.Timestamp(),
 		Model:   x.Model,
 		Choices: []model.ChatCompletionChoice{{
-			Index: chatCompletionRes.Payload.Choices.Seq,
-			Message: &model.ChatCompletionMessage{
-				Role:         chatCompletionRes.Payload.Choices.Text[0].Role,
-				Content:      responseContent,
-				FunctionCall: chatCompletionRes.Payload.Choices.Text[0].FunctionCall,
-			},
+			Index:        chatCompletionRes.Payload.Choices.Seq,
+			Message:      message,
+			FinishReason: convFinishReason(functionCall != nil, isToolCall),
 		}},
 		Usage: &model.Usage{
 			PromptTokens:     chatCompletionRes.Payload.Usage.Text.PromptTokens,
@@ -125,6 +154,9 @@ func (x *Xfyun) ChatCompletionsStream(ctx context.Context, data any) (responseCh
 		}
 	}()
 
+	// req_data透传时为星火原生请求, 函数调用以function_call格式返回
+	isToolCall := false
+
 	if !slices.Contains(x.ReqPassthroughParams, "req_data") {
 
 		request, err := x.ConvChatCompletionsRequest(ctx, data)
@@ -133,12 +165,20 @@ func (x *Xfyun) ChatCompletionsStream(ctx context.Context, data any) (responseCh
 			return responseChan, err
 		}
 
-		if data, err = x.ConvChatCompletionsRequestOfficial(ctx, request); err != nil {
+		isToolCall = isToolCallRequest(request)
+
+		if x.isHttp {
+			data = request
+		} else if data, err = x.ConvChatCompletionsRequestOfficial(ctx, request); err != nil {
 			logger.Errorf(ctx, "ChatCompletionsStream Xfyun ConvChatCompletionsRequestOfficial error: %v", err)
 			return responseChan, err
 		}
 	}
 
+	if x.isHttp {
+		return x.chatCompletionsStreamHttp(ctx, data, now)
+	}
+
 	conn, err := util.WebSocketClient(ctx, x.getWebSocketUrl(ctx), nil, websocket.TextMessage, gjson.MustEncode(data), x.ProxyUrl)
 	if err != nil {
 		logger.Errorf(ctx, "ChatCompletionsStream Xfyun model: %s, error: %v", x.Model, err)
@@ -160,7 +200,10 @@ func (x *Xfyun) ChatCompletionsStream(ctx context.Context, data any) (responseCh
 			logger.Infof(ctx, "ChatCompletionsStream Xfyun model: %s connTime: %d ms, duration: %d ms, totalTime: %d ms", x.Model, duration-now, end-duration, end-now)
 		}()
 
-		var created = gtime.Timestamp()
+		var (
+			created         = gtime.Timestamp()
+			hasFunctionCall = false
+		)
 
 		for {
 
@@ -223,15 +266,15 @@ func (x *Xfyun) ChatCompletionsStream(ctx context.Context, data any) (responseCh
 				Model:   x.Model,
 				Choices: []model.ChatCompletionChoice{{
 					Index: chatCompletionRes.Payload.Choices.Seq,
-					Delta: &model.ChatCompletionStreamChoiceDelta{
-						Role:         chatCompletionRes.Payload.Choices.Text[0].Role,
-						Content:      chatCompletionRes.Payload.Choices.Text[0].Content,
-						FunctionCall: chatCompletionRes.Payload.Choices.Text[0].FunctionCall,
-					},
+					Delta: x.convStreamChoiceDelta(chatCompletionRes.Payload.Choices.Text[0], isToolCall),
 				}},
 				ConnTime: duration - now,
 			}
 
+			if chatCompletionRes.Payload.Choices.Text[0].FunctionCall != nil {
+				hasFunctionCall = true
+			}
+
 			if chatCompletionRes.Payload.Usage != nil {
 				response.Usage = &model.Usage{
 					PromptTokens:     chatCompletionRes.Payload.Usage.Text.PromptTokens,
@@ -244,7 +287,7 @@ func (x *Xfyun) ChatCompletionsStream(ctx context.Context, data any) (responseCh
 
 				logger.Infof(ctx, "ChatCompletionsStream Xfyun model: %s finished", x.Model)
 
-				response.Choices[0].FinishReason = consts.FinishReasonStop
+				response.Choices[0].FinishReason = convFinishReason(hasFunctionCall, isToolCall)
 
 				end := gtime.TimestampMilli()
 				response.Duration = end - duration
@@ -275,3 +318,105 @@ func (x *Xfyun) ChatCompletionsStream(ctx context.Context, data any) (responseCh
 
 	return responseChan, nil
 }
+
+// OpenAI兼容的HTTP接口
+func (x *Xfyun) chatCompletionsHttp(ctx context.Context, data any) (response model.ChatCompletionResponse, err error) {
+
+	bytes, responseHeader, err := util.HttpPost(ctx, x.BaseUrl+x.Path, x.header, data, nil, x.Timeout, x.ProxyUrl, x.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "ChatCompletions Xfyun model: %s, error: %v", x.Model, err)
+		return response, err
+	}
+
+	if response, err = x.ConvChatCompletionsResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "ChatCompletions Xfyun ConvChatCompletionsResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "ChatCompletions Xfyun model: %s finished", x.Model)
+
+	return response, nil
+}
+
+// OpenAI兼容的HTTP接口, 使用SSE流式返回
+func (x *Xfyun) chatCompletionsStreamHttp(ctx context.Context, data any, now int64) (responseChan chan *model.ChatCompletionResponse, err error) {
+
+	stream, err := util.SSEClient(ctx, x.BaseUrl+x.Path, x.header, data, x.Timeout, x.ProxyUrl, x.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "ChatCompletionsStream Xfyun model: %s, error: %v", x.Model, err)
+		return responseChan, err
+	}
+
+	streamResponseHeaders := stream.Response.Header
+
+	duration := gtime.TimestampMilli()
+
+	responseChan = make(chan *model.ChatCompletionResponse)
+
+	if err = grpool.AddWithRecover(ctx, func(ctx context.Context) {
+
+		defer func() {
+			if err := stream.Close(); err != nil {
+				logger.Errorf(ctx, "ChatCompletionsStream Xfyun model: %s, stream.Close error: %v", x.Model, err)
+			}
+
+			end := gtime.TimestampMilli()
+			logger.Infof(ctx, "ChatCompletionsStream Xfyun model: %s connTime: %d ms, duration: %d ms, totalTime: %d ms", x.Model, duration-now, end-duration, end-now)
+		}()
+
+		for {
+
+			responseBytes, err := stream.Recv()
+			if err != nil {
+
+				if errors.Is(err, io.EOF) {
+					logger.Infof(ctx, "ChatCompletionsStream Xfyun model: %s finished", x.Model)
+				} else {
+					logger.Errorf(ctx, "ChatCompletionsStream Xfyun model: %s, error: %v", x.Model, err)
+				}
+
+				end := gtime.TimestampMilli()
+				responseChan <- &model.ChatCompletionResponse{
+					ConnTime:  duration - now,
+					Duration:  end - duration,
+					TotalTime: end - now,
+					Error:     err,
+				}
+
+				return
+			}
+
+			response, err := x.ConvChatCompletionsStreamResponse(ctx, responseBytes)
+			if err != nil {
+				logger.Errorf(ctx, "ChatCompletionsStream Xfyun ConvChatCompletionsStreamResponse error: %v", err)
+
+				end := gtime.TimestampMilli()
+				responseChan <- &model.ChatCompletionResponse{
+					ConnTime:  duration - now,
+					Duration:  end - duration,
+					TotalTime: end - now,
+					Error:     err,
+				}
+
+				return
+			}
+
+			end := gtime.TimestampMilli()
+
+			response.ConnTime = duration - now
+			response.Duration = end - duration
+			response.TotalTime = end - now
+			response.ResponseHeaders = streamResponseHeaders
+
+			responseChan <- &response
+		}
+
+	}, nil); err != nil {
+		logger.Errorf(ctx, "ChatCompletionsStream Xfyun model: %s, error: %v", x.Model, err)
+		return responseChan, err
+	}
+
+	return responseChan, nil
+}
@@ -7,10 +7,15 @@ import (
 
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/gogf/gf/v2/util/grand"
 	"github.com/iimeta/fastapi-sdk/v2/common"
 	"github.com/iimeta/fastapi-sdk/v2/consts"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (x *Xfyun) ConvChatCompletionsRequest(ctx context.Context, data any) (request model.ChatCompletionRequest, err error) {
@@ -34,22 +39,38 @@ func (x *Xfyun) ConvChatCompletionsRequest(ctx context.Context, data any) (reque
 		}
 	}
 
+	isSupportSystemRole := true
 	if x.IsSupportSystemRole != nil {
-		request.Messages = common.HandleMessages(request.Messages, *x.IsSupportSystemRole)
-	} else {
-		request.Messages = common.HandleMessages(request.Messages, true)
+		isSupportSystemRole = *x.IsSupportSystemRole
 	}
 
+	// 星火要求以user开始且user/assistant交替
+	request.Messages = common.HandleMessagesWithOptions(request.Messages, common.MessagesOptions{
+		IsSupportSystemRole: isSupportSystemRole,
+		IsMergeSameRole:     true,
+		IsUserFirst:         true,
+		IsStrictAlternation: true,
+	})
+
 	return request, nil
 }
 
+// 无法得知原请求是否使用tools, 函数调用以function_call格式返回
 func (x *Xfyun) ConvChatCompletionsResponse(ctx context.Context, data []byte) (response model.ChatCompletionResponse, err error) {
+	return x.convChatCompletionsResponse(ctx, data, false)
+}
+
+func (x *Xfyun) convChatCompletionsResponse(ctx context.Context, data []byte, isToolCall bool) (response model.ChatCompletionResponse, err error) {
 
 	now := gtime.TimestampMilli()
 	defer func() {
 		logger.Debugf(ctx, "ConvChatCompletionsResponse time: %d", gtime.TimestampMilli()-now)
 	}()
 
+	if x.isHttp {
+		return x.convChatCompletionsHttpResponse(ctx, data)
+	}
+
 	chatCompletionRes := model.XfyunChatCompletionRes{}
 	if err = json.Unmarshal(data, &chatCompletionRes); err != nil {
 		logger.Error(ctx, err)
@@ -65,36 +86,62 @@ func (x *Xfyun) ConvChatCompletionsResponse(ctx context.Context, data []byte) (r
 		return response, err
 	}
 
+	text := chatCompletionRes.Payload.Choices.Text[0]
+
+	message := &model.ChatCompletionMessage{
+		Role:             text.Role,
+		Content:          text.Content,
+		ReasoningContent: text.ReasoningContent,
+	}
+
+	if text.FunctionCall != nil {
+		if isToolCall {
+			message.ToolCalls = x.convToolCalls(text.FunctionCall, false)
+		} else {
+			message.FunctionCall = text.FunctionCall
+		}
+	}
+
 	response = model.ChatCompletionResponse{
 		Id:      consts.COMPLETION_ID_PREFIX + chatCompletionRes.Header.Sid,
 		Object:  consts.COMPLETION_OBJECT,
 		Created: gtime.Timestamp(),
 		Model:   x.Model,
 		Choices: []model.ChatCompletionChoice{{
-			Index: chatCompletionRes.Payload.Choices.Seq,
-			Message: &model.ChatCompletionMessage{
-				Role:         chatCompletionRes.Payload.Choices.Text[0].Role,
-				FunctionCall: chatCompletionRes.Payload.Choices.Text[0].FunctionCall,
-			},
+			Index:        chatCompletionRes.Payload.Choices.Seq,
+			Message:      message,
+			FinishReason: convFinishReason(text.FunctionCall != nil, isToolCall),
 		}},
-		Usage: &model.Usage{
+		ResponseBytes: data,
+	}
+
+	if chatCompletionRes.Payload.Usage != nil {
+		response.Usage = &model.Usage{
 			PromptTokens:     chatCompletionRes.Payload.Usage.Text.PromptTokens,
 			CompletionTokens: chatCompletionRes.Payload.Usage.Text.CompletionTokens,
 			TotalTokens:      chatCompletionRes.Payload.Usage.Text.TotalTokens,
-		},
-		ResponseBytes: data,
+		}
 	}
 
 	return response, nil
 }
 
+// 无法得知原请求是否使用tools, 函数调用以function_call格式返回
 func (x *Xfyun) ConvChatCompletionsStreamResponse(ctx context.Context, data []byte) (response model.ChatCompletionResponse, err error) {
+	return x.convChatCompletionsStreamResponse(ctx, data, false)
+}
+
+func (x *Xfyun) convChatCompletionsStreamResponse(ctx context.Context, data []byte, isToolCall bool) (response model.ChatCompletionResponse, err error) {
 
 	now := gtime.TimestampMilli()
 	defer func() {
 		logger.Debugf(ctx, "ConvChatCompletionsStreamResponse time: %d", gtime.TimestampMilli()-now)
 	}()
 
+	if x.isHttp {
+		return x.convChatCompletionsHttpResponse(ctx, data)
+	}
+
 	chatCompletionRes := model.XfyunChatCompletionRes{}
 	if err = json.Unmarshal(data, &chatCompletionRes); err != nil {
 		logger.Error(ctx, err)
@@ -117,15 +164,15 @@ func (x *Xfyun) ConvChatCompletionsStreamResponse(ctx context.Context, data []by
 		Model:   x.Model,
 		Choices: []model.ChatCompletionChoice{{
 			Index: chatCompletionRes.Payload.Choices.Seq,
-			Delta: &model.ChatCompletionStreamChoiceDelta{
-				Role:         chatCompletionRes.Payload.Choices.Text[0].Role,
-				Content:      chatCompletionRes.Payload.Choices.Text[0].Content,
-				FunctionCall: chatCompletionRes.Payload.Choices.Text[0].FunctionCall,
-			},
+			Delta: x.convStreamChoiceDelta(chatCompletionRes.Payload.Choices.Text[0], isToolCall),
 		}},
 		ResponseBytes: data,
 	}
 
+	if chatCompletionRes.Header.Status == 2 {
+		response.Choices[0].FinishReason = convFinishReason(chatCompletionRes.Payload.Choices.Text[0].FunctionCall != nil, isToolCall)
+	}
+
 	if chatCompletionRes.Payload.Usage != nil {
 		response.Usage = &model.Usage{
 			PromptTokens:     chatCompletionRes.Payload.Usage.Text.PromptTokens,
@@ -137,6 +184,79 @@ func (x *Xfyun) ConvChatCompletionsStreamResponse(ctx context.Context, data []by
 	return response, nil
 }
 
+// OpenAI兼容接口的响应已是OpenAI格式, X1模型的思考内容在reasoning_content中返回
+func (x *Xfyun) convChatCompletionsHttpResponse(ctx context.Context, data []byte) (response model.ChatCompletionResponse, err error) {
+
+	if err = x.httpApiErrorHandler(data); err != nil {
+		logger.Errorf(ctx, "convChatCompletionsHttpResponse Xfyun model: %s, data: %s, error: %v", x.Model, data, err)
+		return response, err
+	}
+
+	response.ResponseBytes = data
+
+	if err = json.Unmarshal(data, &response); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	if !gstr.HasPrefix(response.Id, consts.COMPLETION_ID_PREFIX) {
+		response.Id = consts.COMPLETION_ID_PREFIX + response.Id
+	}
+
+	return response, nil
+}
+
+func (x *Xfyun) convStreamChoiceDelta(text model.Text, isToolCall bool) *model.ChatCompletionStreamChoiceDelta {
+
+	delta := &model.ChatCompletionStreamChoiceDelta{
+		Role:             text.Role,
+		Content:          text.Content,
+		ReasoningContent: text.ReasoningContent,
+	}
+
+	if text.FunctionCall != nil {
+		if isToolCall {
+			delta.ToolCalls = x.convToolCalls(text.FunctionCall, true)
+		} else {
+			delta.FunctionCall = text.FunctionCall
+		}
+	}
+
+	return delta
+}
+
+// 星火一次只返回一个完整的function_call, 转换为单个tool_call
+func (x *Xfyun) convToolCalls(functionCall *model.FunctionCall, isStream bool) []model.ToolCall {
+
+	toolCall := model.ToolCall{
+		Id:   "call_" + grand.S(24),
+		Type: "function",
+		Function: model.FunctionCall{
+			Name:      functionCall.Name,
+			Arguments: gconv.String(functionCall.Arguments),
+		},
+	}
+
+	if isStream {
+		toolCall.Index = new(int)
+	}
+
+	return []model.ToolCall{toolCall}
+}
+
+func convFinishReason(isFunctionCall, isToolCall bool) string {
+
+	if !isFunctionCall {
+		return consts.FinishReasonStop
+	}
+
+	if isToolCall {
+		return consts.FinishReasonToolCalls
+	}
+
+	return consts.FinishReasonFunctionCall
+}
+
 func (x *Xfyun) ConvChatResponsesRequest(ctx context.Context, data []byte) (request model.ChatCompletionRequest, err error) {
 	//TODO implement me
 	panic("implement me")
@@ -182,13 +302,44 @@ func (x *Xfyun) ConvImageEditsResponse(ctx context.Context, data []byte) (respon
 }
 
 func (x *Xfyun) ConvAudioSpeechRequest(ctx context.Context, data []byte) (request model.SpeechRequest, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvAudioSpeechRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	if err = json.Unmarshal(data, &request); err != nil {
+		logger.Error(ctx, err)
+		return request, err
+	}
+
+	return request, nil
 }
 
+// ConvAudioSpeechResponse data为拼接后的完整音频, wav格式时为PCM添加文件头
 func (x *Xfyun) ConvAudioSpeechResponse(ctx context.Context, data []byte) (response model.SpeechResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return x.convAudioSpeechResponse(ctx, data, "")
+}
+
+// responseFormat为wav时将PCM封装为WAV, 其它格式原样返回
+func (x *Xfyun) convAudioSpeechResponse(ctx context.Context, data []byte, responseFormat string) (response model.SpeechResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvAudioSpeechResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	if len(data) == 0 {
+		return response, errors.New("no audio data in response")
+	}
+
+	response.Data = data
+
+	if responseFormat == "wav" {
+		response.Data = util.PcmToWav(data, 16000, 1, 16)
+	}
+
+	return response, nil
 }
 
 func (x *Xfyun) ConvAudioTranscriptionsRequest(ctx context.Context, request model.AudioRequest) (data *bytes.Buffer, err error) {
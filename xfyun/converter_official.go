@@ -2,11 +2,16 @@ package xfyun
 
 import (
 	"context"
+	"encoding/json"
+	"math"
 
+	"github.com/gogf/gf/v2/encoding/gbase64"
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/gogf/gf/v2/util/grand"
 	"github.com/iimeta/fastapi-sdk/v2/consts"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
 )
@@ -48,14 +53,56 @@ func (x *Xfyun) ConvChatCompletionsRequestOfficial(ctx context.Context, request
 		},
 	}
 
-	if request.Functions != nil && len(request.Functions) > 0 {
+	functions := request.Functions
+
+	// 星火不支持tool_choice, none时不传函数, 指定函数时只传该函数, 无法强制模型调用函数
+	if gconv.String(request.ToolChoice) == "required" {
+		return nil, errors.New("Xfyun does not support tool_choice required")
+	}
+
+	if isToolCallRequest(request) {
+
+		name := gconv.String(gconv.Map(gconv.Map(request.ToolChoice)["function"])["name"])
+
+		for _, function := range convFunctions(request.Tools) {
+			if name == "" || function.Name == name {
+				functions = append(functions, function)
+			}
+		}
+	}
+
+	if len(functions) > 0 && gconv.String(request.FunctionCall) != "none" {
 		chatCompletionReq.Payload.Functions = new(model.Functions)
-		chatCompletionReq.Payload.Functions.Text = append(chatCompletionReq.Payload.Functions.Text, request.Functions...)
+		chatCompletionReq.Payload.Functions.Text = append(chatCompletionReq.Payload.Functions.Text, functions...)
 	}
 
 	return gjson.MustEncode(chatCompletionReq), nil
 }
 
+// 请求使用tools时以tool_calls格式返回函数调用, 否则以function_call格式返回
+func isToolCallRequest(request model.ChatCompletionRequest) bool {
+	return request.Tools != nil && gconv.String(request.ToolChoice) != "none"
+}
+
+func convFunctions(tools any) (functions []model.FunctionDefinition) {
+
+	for _, tool := range gconv.Maps(tools) {
+
+		if tool["type"] != nil && tool["type"] != "function" {
+			continue
+		}
+
+		function := model.FunctionDefinition{}
+		if err := json.Unmarshal(gjson.MustEncode(tool["function"]), &function); err != nil || function.Name == "" {
+			continue
+		}
+
+		functions = append(functions, function)
+	}
+
+	return functions
+}
+
 func (x *Xfyun) ConvChatCompletionsResponseOfficial(ctx context.Context, response model.ChatCompletionResponse) ([]byte, error) {
 	//TODO implement me
 	panic("implement me")
@@ -85,3 +132,66 @@ func (x *Xfyun) ConvImageEditsResponseOfficial(ctx context.Context, response mod
 	//TODO implement me
 	panic("implement me")
 }
+
+func (x *Xfyun) ConvAudioSpeechRequestOfficial(ctx context.Context, request model.SpeechRequest) ([]byte, error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvAudioSpeechRequestOfficial time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	ttsReq := model.XfyunTtsReq{
+		Common: model.XfyunCommon{
+			AppId: x.appId,
+		},
+		Business: model.XfyunTtsBusiness{
+			Aue:    "raw",
+			Auf:    "audio/L16;rate=16000",
+			Vcn:    convVoice(request.Voice),
+			Speed:  50,
+			Volume: 50,
+			Pitch:  50,
+			Tte:    "UTF8",
+		},
+		Data: model.XfyunTtsData{
+			Status: 2,
+			Text:   gbase64.EncodeString(request.Input),
+		},
+	}
+
+	// OpenAI默认返回mp3
+	if request.ResponseFormat == "" || request.ResponseFormat == "mp3" {
+		ttsReq.Business.Aue = "lame"
+		ttsReq.Business.Sfl = 1
+	}
+
+	// OpenAI语速范围[0.25,4.0], 默认1.0, 讯飞语速范围[0,100], 默认50
+	if request.Speed > 0 {
+		ttsReq.Business.Speed = int(math.Min(math.Round(request.Speed*50), 100))
+	}
+
+	return gjson.MustEncode(ttsReq), nil
+}
+
+// OpenAI音色映射为讯飞基础发音人, 其它音色直接作为发音人使用
+var voiceMapping = map[string]string{
+	"alloy":   "xiaoyan",
+	"echo":    "aisjiuxu",
+	"fable":   "aisbabyxu",
+	"onyx":    "aisjiuxu",
+	"nova":    "aisxping",
+	"shimmer": "aisjinger",
+}
+
+func convVoice(voice string) string {
+
+	if voice == "" {
+		return "xiaoyan"
+	}
+
+	if vcn, ok := voiceMapping[voice]; ok {
+		return vcn
+	}
+
+	return voice
+}
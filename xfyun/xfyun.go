@@ -3,7 +3,9 @@ package xfyun
 import (
 	"context"
 	"crypto/hmac"
+	"crypto/sha1"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math"
@@ -12,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gogf/gf/v2/crypto/gmd5"
 	"github.com/gogf/gf/v2/encoding/gbase64"
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/encoding/gurl"
@@ -31,6 +34,8 @@ type Xfyun struct {
 	secret      string
 	originalUrl string
 	domain      string
+	isHttp      bool   // 是否使用OpenAI兼容的HTTP接口
+	customUrl   string // 自定义的完整请求地址, 语音服务未配置时使用默认地址
 }
 
 func NewAdapter(ctx context.Context, options *options.AdapterOptions) *Xfyun {
@@ -47,6 +52,10 @@ func NewAdapter(ctx context.Context, options *options.AdapterOptions) *Xfyun {
 
 	xfyun.Key = result[2]
 
+	if xfyun.BaseUrl != "" {
+		xfyun.customUrl = xfyun.BaseUrl + xfyun.Path
+	}
+
 	if xfyun.BaseUrl == "" {
 		xfyun.BaseUrl = "https://spark-api.xf-yun.com/v4.0"
 	}
@@ -68,6 +77,8 @@ func NewAdapter(ctx context.Context, options *options.AdapterOptions) *Xfyun {
 		xfyun.domain = "generalv2"
 	case "v1.1":
 		xfyun.domain = "general"
+	case "x1":
+		xfyun.domain = "x1"
 	default:
 		v := gconv.Float64(version[1:])
 		if math.Round(v) > v {
@@ -92,6 +103,43 @@ func NewAdapter(ctx context.Context, options *options.AdapterOptions) *Xfyun {
 	return xfyun
 }
 
+// NewHttpConverter 星火OpenAI兼容的HTTP接口的转换器, 不修改options
+func NewHttpConverter(options *options.AdapterOptions) *Xfyun {
+	return &Xfyun{AdapterOptions: options, isHttp: true}
+}
+
+// NewHttpAdapter 使用星火OpenAI兼容的HTTP接口, key为控制台获取的APIPassword
+func NewHttpAdapter(ctx context.Context, options *options.AdapterOptions) *Xfyun {
+
+	xfyun := &Xfyun{
+		AdapterOptions: options,
+		header: map[string]string{
+			"Authorization": "Bearer " + options.Key,
+		},
+		isHttp: true,
+	}
+
+	if xfyun.BaseUrl == "" {
+		xfyun.BaseUrl = "https://spark-api-open.xf-yun.com/v1"
+	}
+
+	if xfyun.Path == "" {
+		xfyun.Path = "/chat/completions"
+	}
+
+	for k, v := range xfyun.PassthroughHeader {
+		xfyun.header[k] = v
+	}
+
+	for k, v := range xfyun.Header {
+		xfyun.header[k] = v
+	}
+
+	logger.Infof(ctx, "NewHttpAdapter Xfyun model: %s, key: %s", xfyun.Model, xfyun.Key)
+
+	return xfyun
+}
+
 func (x *Xfyun) getWebSocketUrl(ctx context.Context) string {
 
 	date, host, signature, err := x.getSignature(ctx, http.MethodGet)
@@ -122,6 +170,55 @@ func (x *Xfyun) getHttpUrl(ctx context.Context) string {
 	return fmt.Sprintf("%s?authorization=%s&date=%s&host=%s", x.BaseUrl+x.Path, authorizationOrigin, date, host)
 }
 
+// 语音合成和语音听写的鉴权地址, 签名方式与星火大模型一致, host和path取自请求地址
+func (x *Xfyun) getAudioUrl(ctx context.Context, rawURL string) string {
+
+	parse, err := url.Parse(rawURL)
+	if err != nil {
+		logger.Errorf(ctx, "getAudioUrl Xfyun rawURL: %s, error: %s", rawURL, err)
+		return ""
+	}
+
+	now := gtime.Now()
+	loc, _ := time.LoadLocation("GMT")
+	zone, _ := now.ToZone(loc.String())
+	date := zone.Layout("Mon, 02 Jan 2006 15:04:05 GMT")
+
+	tmp := "host: " + parse.Host + "\n"
+	tmp += "date: " + date + "\n"
+	tmp += http.MethodGet + " " + parse.Path + " HTTP/1.1"
+
+	hash := hmac.New(sha256.New, []byte(x.secret))
+
+	if _, err = hash.Write([]byte(tmp)); err != nil {
+		logger.Errorf(ctx, "getAudioUrl Xfyun rawURL: %s, error: %s", rawURL, err)
+		return ""
+	}
+
+	authorizationOrigin := gbase64.EncodeToString([]byte(fmt.Sprintf("api_key=\"%s\",algorithm=\"%s\",headers=\"%s\",signature=\"%s\"", x.Key, "hmac-sha256", "host date request-line", gbase64.EncodeToString(hash.Sum(nil)))))
+
+	wsURL := gstr.Replace(gstr.Replace(rawURL, "https://", "wss://"), "http://", "ws://")
+
+	return fmt.Sprintf("%s?authorization=%s&date=%s&host=%s", wsURL, gurl.RawEncode(authorizationOrigin), gurl.RawEncode(date), parse.Host)
+}
+
+// 实时语音转写的鉴权地址, signa = Base64(HmacSHA1(MD5(appid + ts), apiKey))
+func (x *Xfyun) getRtasrUrl(ctx context.Context, rawURL, lang string) string {
+
+	ts := gconv.String(gtime.Timestamp())
+
+	hash := hmac.New(sha1.New, []byte(x.Key))
+
+	if _, err := hash.Write([]byte(gmd5.MustEncryptString(x.appId + ts))); err != nil {
+		logger.Errorf(ctx, "getRtasrUrl Xfyun rawURL: %s, error: %s", rawURL, err)
+		return ""
+	}
+
+	wsURL := gstr.Replace(gstr.Replace(rawURL, "https://", "wss://"), "http://", "ws://")
+
+	return fmt.Sprintf("%s?appid=%s&ts=%s&signa=%s&lang=%s", wsURL, x.appId, ts, gurl.RawEncode(gbase64.EncodeToString(hash.Sum(nil))), lang)
+}
+
 func (x *Xfyun) getSignature(ctx context.Context, method string) (date, host, signature string, err error) {
 
 	parse, err := url.Parse(x.originalUrl + x.BaseUrl[strings.LastIndex(x.BaseUrl, "/"):] + x.Path)
@@ -166,3 +263,19 @@ func (x *Xfyun) apiErrorHandler(response *model.XfyunChatCompletionRes) error {
 
 	return errors.NewApiError(500, response.Header.Code, gjson.MustEncodeString(response), "api_error", "")
 }
+
+// OpenAI兼容接口的业务错误同样以HTTP状态码200返回, 需根据code判断
+func (x *Xfyun) httpApiErrorHandler(data []byte) error {
+
+	errorRes := model.XfyunHttpErrorRes{}
+	if err := json.Unmarshal(data, &errorRes); err != nil || errorRes.Code == 0 {
+		return nil
+	}
+
+	switch errorRes.Code {
+	case 10163, 10907:
+		return errors.ERR_CONTEXT_LENGTH_EXCEEDED
+	}
+
+	return errors.NewApiError(500, errorRes.Code, string(data), "api_error", "")
+}
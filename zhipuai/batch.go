@@ -2,26 +2,163 @@ package zhipuai
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (z *ZhipuAI) BatchCreate(ctx context.Context, request model.BatchCreateRequest) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchCreate ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchCreate ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	data, err := z.ConvBatchCreateRequest(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "BatchCreate ZhipuAI ConvBatchCreateRequest error: %v", err)
+		return response, err
+	}
+
+	if z.Path == "" {
+		z.Path = "/batches"
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "BatchCreate ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	responseBytes, responseHeader, err := util.HttpPost(ctx, z.BaseUrl+z.Path, header, data.Bytes(), nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "BatchCreate ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvBatchResponse(ctx, responseBytes); err != nil {
+		logger.Errorf(ctx, "BatchCreate ZhipuAI ConvBatchResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseBytes = responseBytes
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "BatchCreate ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
 
 func (z *ZhipuAI) BatchList(ctx context.Context, request model.BatchListRequest) (response model.BatchListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchList ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchList ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	if z.Path == "" {
+		z.Path = "/batches"
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "BatchList ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpGet(ctx, z.BaseUrl+z.Path, header, request, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "BatchList ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvBatchListResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "BatchList ZhipuAI ConvBatchListResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "BatchList ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
 
 func (z *ZhipuAI) BatchRetrieve(ctx context.Context, request model.BatchRetrieveRequest) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchRetrieve ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchRetrieve ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	if z.Path == "" {
+		z.Path = fmt.Sprintf("/batches/%s", request.BatchId)
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "BatchRetrieve ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpGet(ctx, z.BaseUrl+z.Path, header, nil, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "BatchRetrieve ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvBatchResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "BatchRetrieve ZhipuAI ConvBatchResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "BatchRetrieve ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
 
 func (z *ZhipuAI) BatchCancel(ctx context.Context, request model.BatchCancelRequest) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "BatchCancel ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "BatchCancel ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	if z.Path == "" {
+		z.Path = fmt.Sprintf("/batches/%s/cancel", request.BatchId)
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "BatchCancel ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpPost(ctx, z.BaseUrl+z.Path, header, nil, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "BatchCancel ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvBatchResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "BatchCancel ZhipuAI ConvBatchResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "BatchCancel ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
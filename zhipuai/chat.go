@@ -41,7 +41,13 @@ func (z *ZhipuAI) ChatCompletions(ctx context.Context, data any) (response model
 		z.Path = "/chat/completions"
 	}
 
-	bytes, responseHeader, err := util.HttpPost(ctx, z.BaseUrl+z.Path, z.header, data, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "ChatCompletions ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, responseHeader, err := util.HttpPost(ctx, z.BaseUrl+z.Path, header, data, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
 	if err != nil {
 		logger.Errorf(ctx, "ChatCompletions ZhipuAI model: %s, error: %v", z.Model, err)
 		return response, err
@@ -86,7 +92,13 @@ func (z *ZhipuAI) ChatCompletionsStream(ctx context.Context, data any) (response
 		z.Path = "/chat/completions"
 	}
 
-	stream, err := util.SSEClient(ctx, z.BaseUrl+z.Path, z.header, data, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "ChatCompletionsStream ZhipuAI model: %s, error: %v", z.Model, err)
+		return responseChan, err
+	}
+
+	stream, err := util.SSEClient(ctx, z.BaseUrl+z.Path, header, data, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
 	if err != nil {
 		logger.Errorf(ctx, "ChatCompletionsStream ZhipuAI model: %s, error: %v", z.Model, err)
 		return responseChan, err
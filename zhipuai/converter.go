@@ -3,14 +3,23 @@ package zhipuai
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/iimeta/fastapi-sdk/v2/common"
 	"github.com/iimeta/fastapi-sdk/v2/consts"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (z *ZhipuAI) ConvChatCompletionsRequest(ctx context.Context, data any) (request model.ChatCompletionRequest, err error) {
@@ -34,12 +43,19 @@ func (z *ZhipuAI) ConvChatCompletionsRequest(ctx context.Context, data any) (req
 		}
 	}
 
+	isSupportSystemRole := true
 	if z.IsSupportSystemRole != nil {
-		request.Messages = common.HandleMessages(request.Messages, *z.IsSupportSystemRole)
-	} else {
-		request.Messages = common.HandleMessages(request.Messages, true)
+		isSupportSystemRole = *z.IsSupportSystemRole
 	}
 
+	// 智谱要求以user开始且user/assistant交替
+	request.Messages = common.HandleMessagesWithOptions(request.Messages, common.MessagesOptions{
+		IsSupportSystemRole: isSupportSystemRole,
+		IsMergeSameRole:     true,
+		IsUserFirst:         true,
+		IsStrictAlternation: true,
+	})
+
 	return request, nil
 }
 
@@ -75,6 +91,15 @@ func (z *ZhipuAI) ConvChatCompletionsResponse(ctx context.Context, data []byte)
 		ResponseBytes: data,
 	}
 
+	// 联网搜索与知识库检索结果作为消息的annotations返回, reasoning_content与OpenAI格式一致无需转换
+	for _, choice := range response.Choices {
+		if choice.Message != nil {
+			if annotations := convAnnotations(gconv.String(choice.Message.Content), chatCompletionRes.WebSearch, choice.Message.ToolCalls); len(annotations) > 0 {
+				choice.Message.Annotations = annotations
+			}
+		}
+	}
+
 	return response, nil
 }
 
@@ -110,9 +135,108 @@ func (z *ZhipuAI) ConvChatCompletionsStreamResponse(ctx context.Context, data []
 		ResponseBytes: data,
 	}
 
+	// 流式仅在首个分块返回联网搜索结果, 此时正文尚未返回, 不含引用位置
+	for _, choice := range response.Choices {
+		if choice.Delta != nil {
+			if annotations := convAnnotations(choice.Delta.Content, chatCompletionRes.WebSearch, choice.Delta.ToolCalls); len(annotations) > 0 {
+				choice.Delta.Annotations = annotations
+			}
+		}
+	}
+
 	return response, nil
 }
 
+// 联网搜索与内置工具结果转换为annotations, 有链接的转换为url_citation, 知识库检索结果转换为file_citation
+func convAnnotations(content string, webSearch []model.ZhipuAIWebSearchResult, toolCalls any) (annotations []any) {
+
+	for _, result := range webSearch {
+		if result.Link != "" {
+			annotations = append(annotations, convCitations(content, "url_citation", map[string]any{
+				"url":   result.Link,
+				"title": result.Title,
+			}, result.Refer)...)
+		}
+	}
+
+	if toolCalls == nil {
+		return annotations
+	}
+
+	var zhipuToolCalls []model.ZhipuAIToolCall
+	if err := json.Unmarshal(gjson.MustEncode(toolCalls), &zhipuToolCalls); err != nil {
+		return annotations
+	}
+
+	for _, toolCall := range zhipuToolCalls {
+
+		result := toolCall.Retrieval
+		if toolCall.WebBrowser != nil {
+			result = toolCall.WebBrowser
+		}
+
+		if result == nil {
+			continue
+		}
+
+		for _, output := range result.Outputs {
+			if output.Link != "" {
+				annotations = append(annotations, convCitations(content, "url_citation", map[string]any{
+					"url":   output.Link,
+					"title": output.Title,
+				}, output.Refer)...)
+			} else if toolCall.Retrieval != nil {
+				annotations = append(annotations, convCitations(content, "file_citation", map[string]any{
+					"title":   output.Title,
+					"content": output.Content,
+				}, output.Refer)...)
+			}
+		}
+	}
+
+	return annotations
+}
+
+// 按content中的角标(如[ref_1])出现位置生成引用, 位置为字符下标, 找不到角标时不返回位置
+func convCitations(content, citationType string, citation map[string]any, refer string) (annotations []any) {
+
+	if refer != "" {
+		for offset := 0; ; {
+
+			index := strings.Index(content[offset:], refer)
+			if index < 0 {
+				break
+			}
+
+			start := utf8.RuneCountInString(content[:offset+index])
+
+			indexed := make(map[string]any, len(citation)+2)
+			for k, v := range citation {
+				indexed[k] = v
+			}
+
+			indexed["start_index"] = start
+			indexed["end_index"] = start + utf8.RuneCountInString(refer)
+
+			annotations = append(annotations, map[string]any{
+				"type":       citationType,
+				citationType: indexed,
+			})
+
+			offset += index + len(refer)
+		}
+	}
+
+	if len(annotations) == 0 {
+		annotations = append(annotations, map[string]any{
+			"type":       citationType,
+			citationType: citation,
+		})
+	}
+
+	return annotations
+}
+
 func (z *ZhipuAI) ConvChatResponsesRequest(ctx context.Context, data []byte) (request model.ChatCompletionRequest, err error) {
 	//TODO implement me
 	panic("implement me")
@@ -129,13 +253,50 @@ func (z *ZhipuAI) ConvChatResponsesStreamResponse(ctx context.Context, data []by
 }
 
 func (z *ZhipuAI) ConvImageGenerationsRequest(ctx context.Context, data []byte) (request model.ImageGenerationRequest, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvImageGenerationsRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	if err = json.Unmarshal(data, &request); err != nil {
+		logger.Error(ctx, err)
+		return request, err
+	}
+
+	return request, nil
 }
 
 func (z *ZhipuAI) ConvImageGenerationsResponse(ctx context.Context, data []byte) (response model.ImageResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvImageGenerationsResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	imageRes := model.ZhipuAIImageRes{}
+	if err = json.Unmarshal(data, &imageRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	if imageRes.Error != nil && imageRes.Error.Code != "" {
+		logger.Errorf(ctx, "ConvImageGenerationsResponse ZhipuAI model: %s, imageRes: %s", z.Model, data)
+		return response, errors.NewApiError(500, imageRes.Error.Code, string(data), "api_error", "")
+	}
+
+	response = model.ImageResponse{
+		Created:       imageRes.Created,
+		ResponseBytes: data,
+	}
+
+	for _, image := range imageRes.Data {
+		response.Data = append(response.Data, model.ImageResponseData{
+			Url: image.Url,
+		})
+	}
+
+	return response, nil
 }
 
 func (z *ZhipuAI) ConvImageEditsRequest(ctx context.Context, request model.ImageEditRequest) (data *bytes.Buffer, err error) {
@@ -169,18 +330,115 @@ func (z *ZhipuAI) ConvAudioTranscriptionsResponse(ctx context.Context, data []by
 }
 
 func (z *ZhipuAI) ConvTextEmbeddingsRequest(ctx context.Context, data []byte) (request model.EmbeddingRequest, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvTextEmbeddingsRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	if err = json.Unmarshal(data, &request); err != nil {
+		logger.Error(ctx, err)
+		return request, err
+	}
+
+	return request, nil
 }
 
 func (z *ZhipuAI) ConvTextEmbeddingsResponse(ctx context.Context, data []byte) (response model.EmbeddingResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return z.convTextEmbeddingsResponse(ctx, data, "")
+}
+
+func (z *ZhipuAI) convTextEmbeddingsResponse(ctx context.Context, data []byte, encodingFormat string) (response model.EmbeddingResponse, err error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvTextEmbeddingsResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	embeddingRes := model.ZhipuAIEmbeddingRes{}
+	if err = json.Unmarshal(data, &embeddingRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	if embeddingRes.Error != nil && embeddingRes.Error.Code != "" {
+		err = errors.NewApiError(500, embeddingRes.Error.Code, gjson.MustEncodeString(embeddingRes), "api_error", "")
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	response = model.EmbeddingResponse{
+		Object: "list",
+		Model:  embeddingRes.Model,
+		Usage:  embeddingRes.Usage,
+	}
+
+	for _, embedding := range embeddingRes.Data {
+		response.Data = append(response.Data, convEmbedding(embedding.Index, embedding.Embedding, encodingFormat))
+	}
+
+	return response, nil
+}
+
+// 智谱不支持encoding_format, 为base64时按OpenAI的格式返回小端float32数组的base64编码
+func convEmbedding(index int, values []float64, encodingFormat string) map[string]any {
+
+	var embedding any = values
+
+	if encodingFormat == "base64" {
+
+		buf := make([]byte, len(values)*4)
+		for i, value := range values {
+			binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(float32(value)))
+		}
+
+		embedding = base64.StdEncoding.EncodeToString(buf)
+	}
+
+	return map[string]any{
+		"object":    "embedding",
+		"index":     index,
+		"embedding": embedding,
+	}
 }
 
 func (z *ZhipuAI) ConvVideoCreateRequest(ctx context.Context, request model.VideoCreateRequest) (data *bytes.Buffer, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvVideoCreateRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	videoReq := model.ZhipuAIVideoReq{
+		Model:  z.Model,
+		Prompt: request.Prompt,
+		Size:   request.Size,
+	}
+
+	if request.Seconds != "" {
+		videoReq.Duration = gconv.Int(request.Seconds)
+	}
+
+	// 参考图片作为首帧, 图生视频
+	if request.InputReference != nil {
+
+		file, err := request.InputReference.Open()
+		if err != nil {
+			logger.Errorf(ctx, "ConvVideoCreateRequest ZhipuAI model: %s, error: %v", z.Model, err)
+			return nil, err
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			logger.Errorf(ctx, "ConvVideoCreateRequest ZhipuAI model: %s, error: %v", z.Model, err)
+			return nil, err
+		}
+
+		videoReq.ImageUrl = base64.StdEncoding.EncodeToString(content)
+	}
+
+	return bytes.NewBuffer(gjson.MustEncode(videoReq)), nil
 }
 
 func (z *ZhipuAI) ConvVideoListResponse(ctx context.Context, data []byte) (response model.VideoListResponse, err error) {
@@ -194,41 +452,340 @@ func (z *ZhipuAI) ConvVideoContentResponse(ctx context.Context, data []byte) (re
 }
 
 func (z *ZhipuAI) ConvVideoJobResponse(ctx context.Context, data []byte) (response model.VideoJobResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvVideoJobResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	asyncRes := model.ZhipuAIAsyncRes{}
+	if err = json.Unmarshal(data, &asyncRes); err != nil {
+		logger.Errorf(ctx, "ConvVideoJobResponse ZhipuAI json.Unmarshal error: %v", err)
+		return response, err
+	}
+
+	response = model.VideoJobResponse{
+		Id:            asyncRes.Id,
+		Object:        "video",
+		Model:         asyncRes.Model,
+		Status:        convZhipuAIStatus(asyncRes.TaskStatus),
+		ResponseBytes: data,
+	}
+
+	if response.Model == "" {
+		response.Model = z.Model
+	}
+
+	if len(asyncRes.VideoResult) > 0 {
+		response.VideoUrl = asyncRes.VideoResult[0].Url
+	}
+
+	switch response.Status {
+	case "completed":
+		// 智谱不返回完成时间, CompletedAt留空
+		response.Progress = 100
+	case "failed":
+		response.Error = &model.VideoError{
+			Code:    asyncRes.TaskStatus,
+			Message: "video generation failed",
+		}
+		if asyncRes.Error != nil {
+			response.Error.Code = asyncRes.Error.Code
+			response.Error.Message = asyncRes.Error.Message
+		}
+	}
+
+	return response, nil
+}
+
+// 将智谱异步任务状态映射到系统标准状态
+func convZhipuAIStatus(status string) string {
+	switch status {
+	case "PROCESSING":
+		return "in_progress"
+	case "SUCCESS":
+		return "completed"
+	case "FAIL":
+		return "failed"
+	default:
+		return status
+	}
 }
 
 func (z *ZhipuAI) ConvFileUploadRequest(ctx context.Context, request model.FileUploadRequest) (data *bytes.Buffer, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvFileUploadRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	data = &bytes.Buffer{}
+	builder := util.NewFormBuilder(data)
+
+	defer func() {
+		if err := builder.Close(); err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest ZhipuAI model: %s, builder.Close() error: %v", z.Model, err)
+		}
+	}()
+
+	if request.File != nil && request.Purpose == "batch" {
+
+		// 批处理输入文件需在上传时转换为智谱的请求格式
+		file, err := request.File.Open()
+		if err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest ZhipuAI model: %s, error: %v", z.Model, err)
+			return data, err
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest ZhipuAI model: %s, error: %v", z.Model, err)
+			return data, err
+		}
+
+		if content, err = z.ConvBatchInputFile(ctx, content); err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest ZhipuAI model: %s, error: %v", z.Model, err)
+			return data, err
+		}
+
+		if err = builder.CreateFormFileReader("file", bytes.NewReader(content), request.File.Filename); err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest ZhipuAI model: %s, error: %v", z.Model, err)
+			return data, err
+		}
+
+	} else if request.File != nil {
+		if err = builder.CreateFormFileHeader("file", request.File); err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest ZhipuAI model: %s, error: %v", z.Model, err)
+			return data, err
+		}
+	}
+
+	if request.Purpose != "" {
+		if err = builder.WriteField("purpose", request.Purpose); err != nil {
+			logger.Errorf(ctx, "ConvFileUploadRequest ZhipuAI model: %s, error: %v", z.Model, err)
+			return data, err
+		}
+	}
+
+	z.header["Content-Type"] = builder.FormDataContentType()
+
+	return data, nil
 }
 
 func (z *ZhipuAI) ConvFileListResponse(ctx context.Context, data []byte) (response model.FileListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvFileListResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	response.ResponseBytes = data
+
+	if err = json.Unmarshal(data, &response); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	return response, nil
 }
 
 func (z *ZhipuAI) ConvFileContentResponse(ctx context.Context, data []byte) (response model.FileContentResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvFileContentResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	return model.FileContentResponse{
+		Data: data,
+	}, nil
+}
+
+// OpenAI的批处理输入文件转换为智谱的批处理输入格式, url为/v4/chat/completions, body按智谱的请求参数转换
+func (z *ZhipuAI) ConvBatchInputFile(ctx context.Context, data []byte) ([]byte, error) {
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchInputFile time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	buf := &bytes.Buffer{}
+
+	for i, line := range bytes.Split(data, []byte("\n")) {
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		batchInput := struct {
+			CustomId string          `json:"custom_id"`
+			Method   string          `json:"method"`
+			Url      string          `json:"url"`
+			Body     json.RawMessage `json:"body"`
+		}{}
+
+		if err := json.Unmarshal(line, &batchInput); err != nil {
+			logger.Errorf(ctx, "ConvBatchInputFile ZhipuAI model: %s, line: %d, error: %v", z.Model, i+1, err)
+			return nil, err
+		}
+
+		zhipuaiBatchInput := model.ZhipuAIBatchInput{
+			CustomId: batchInput.CustomId,
+			Method:   batchInput.Method,
+			Url:      convEndpoint(batchInput.Url),
+			Body:     batchInput.Body,
+		}
+
+		if zhipuaiBatchInput.Method == "" {
+			zhipuaiBatchInput.Method = "POST"
+		}
+
+		if strings.HasSuffix(zhipuaiBatchInput.Url, "/chat/completions") {
+
+			chatCompletionRequest := model.ChatCompletionRequest{}
+			if err := json.Unmarshal(batchInput.Body, &chatCompletionRequest); err != nil {
+				logger.Errorf(ctx, "ConvBatchInputFile ZhipuAI model: %s, line: %d, error: %v", z.Model, i+1, err)
+				return nil, err
+			}
+
+			// 批处理不支持流式输出
+			chatCompletionRequest.Stream = false
+
+			body, err := z.ConvChatCompletionsRequestOfficial(ctx, chatCompletionRequest)
+			if err != nil {
+				logger.Errorf(ctx, "ConvBatchInputFile ZhipuAI model: %s, line: %d, error: %v", z.Model, i+1, err)
+				return nil, err
+			}
+
+			chatCompletionReq := model.ZhipuAIChatCompletionReq{}
+			if err = json.Unmarshal(body, &chatCompletionReq); err != nil {
+				logger.Errorf(ctx, "ConvBatchInputFile ZhipuAI model: %s, line: %d, error: %v", z.Model, i+1, err)
+				return nil, err
+			}
+
+			// 优先使用每行请求指定的模型
+			if chatCompletionRequest.Model != "" {
+				chatCompletionReq.Model = chatCompletionRequest.Model
+			}
+
+			zhipuaiBatchInput.Body = chatCompletionReq
+
+		} else if strings.HasSuffix(zhipuaiBatchInput.Url, "/embeddings") {
+
+			embeddingRequest := model.EmbeddingRequest{}
+			if err := json.Unmarshal(batchInput.Body, &embeddingRequest); err != nil {
+				logger.Errorf(ctx, "ConvBatchInputFile ZhipuAI model: %s, line: %d, error: %v", z.Model, i+1, err)
+				return nil, err
+			}
+
+			embeddingReq := model.ZhipuAIEmbeddingReq{
+				Model:      embeddingRequest.Model,
+				Input:      embeddingRequest.Input,
+				Dimensions: embeddingRequest.Dimensions,
+			}
+
+			if embeddingReq.Model == "" {
+				embeddingReq.Model = z.Model
+			}
+
+			zhipuaiBatchInput.Body = embeddingReq
+		}
+
+		buf.Write(gjson.MustEncode(zhipuaiBatchInput))
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
 }
 
 func (z *ZhipuAI) ConvFileResponse(ctx context.Context, data []byte) (response model.FileResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvFileResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	response.ResponseBytes = data
+
+	if err = json.Unmarshal(data, &response); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	return response, nil
 }
 
 func (z *ZhipuAI) ConvBatchCreateRequest(ctx context.Context, request model.BatchCreateRequest) (data *bytes.Buffer, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchCreateRequest time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	batchCreateReq := model.ZhipuAIBatchCreateReq{
+		InputFileId:      request.InputFileId,
+		Endpoint:         convEndpoint(request.Endpoint),
+		CompletionWindow: request.CompletionWindow,
+		Metadata:         request.Metadata,
+	}
+
+	return bytes.NewBuffer(gjson.MustEncode(batchCreateReq)), nil
 }
 
 func (z *ZhipuAI) ConvBatchListResponse(ctx context.Context, data []byte) (response model.BatchListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchListResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	batchListRes := struct {
+		model.BatchListResponse
+		Data []model.BatchResponse `json:"data"`
+	}{}
+
+	if err = json.Unmarshal(data, &batchListRes); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	response = batchListRes.BatchListResponse
+
+	for _, batch := range batchListRes.Data {
+		batch.Endpoint = strings.Replace(batch.Endpoint, "/v4/", "/v1/", 1)
+		response.Data = append(response.Data, batch)
+	}
+
+	return response, nil
 }
 
 func (z *ZhipuAI) ConvBatchResponse(ctx context.Context, data []byte) (response model.BatchResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvBatchResponse time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	response.ResponseBytes = data
+
+	if err = json.Unmarshal(data, &response); err != nil {
+		logger.Error(ctx, err)
+		return response, err
+	}
+
+	// 智谱的接口路径为/v4, 按OpenAI的格式返回
+	response.Endpoint = strings.Replace(response.Endpoint, "/v4/", "/v1/", 1)
+
+	return response, nil
+}
+
+// OpenAI的接口路径/v1/chat/completions转换为智谱的/v4/chat/completions
+func convEndpoint(endpoint string) string {
+
+	if strings.HasPrefix(endpoint, "/v1/") {
+		return "/v4/" + strings.TrimPrefix(endpoint, "/v1/")
+	}
+
+	return endpoint
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
 )
@@ -29,6 +30,18 @@ func (z *ZhipuAI) ConvChatCompletionsRequestOfficial(ctx context.Context, reques
 		UserId:      request.User,
 	}
 
+	if request.ReasoningEffort != "" || request.EnableThinking != nil {
+		chatCompletionReq.Thinking = convThinking(request)
+	}
+
+	if request.WebSearchOptions != nil {
+		chatCompletionReq.Tools = convWebSearchTool(request.Tools, request.WebSearchOptions)
+	}
+
+	if chatCompletionReq.MaxTokens == 0 && request.MaxCompletionTokens != 0 {
+		chatCompletionReq.MaxTokens = request.MaxCompletionTokens
+	}
+
 	if chatCompletionReq.TopP == 1 {
 		chatCompletionReq.TopP -= 0.01
 	} else if chatCompletionReq.TopP == 0 {
@@ -48,6 +61,52 @@ func (z *ZhipuAI) ConvChatCompletionsRequestOfficial(ctx context.Context, reques
 	return gjson.MustEncode(chatCompletionReq), nil
 }
 
+// reasoning_effort/enable_thinking转换为thinking, 智谱仅支持开启或关闭思考
+func convThinking(request model.ChatCompletionRequest) *model.ZhipuAIThinking {
+
+	thinking := &model.ZhipuAIThinking{
+		Type: "enabled",
+	}
+
+	if request.EnableThinking != nil && !*request.EnableThinking {
+		thinking.Type = "disabled"
+	}
+
+	if request.ReasoningEffort == "none" || request.ReasoningEffort == "minimal" {
+		thinking.Type = "disabled"
+	}
+
+	return thinking
+}
+
+// web_search_options转换为智谱的web_search工具, 已包含web_search工具时不重复添加
+func convWebSearchTool(tools any, webSearchOptions any) []any {
+
+	result := gconv.Interfaces(tools)
+
+	for _, tool := range gconv.Maps(tools) {
+		if tool["type"] == "web_search" {
+			return result
+		}
+	}
+
+	webSearch := model.ZhipuAIWebSearchTool{
+		Enable:       true,
+		SearchEngine: "search_std",
+		SearchResult: true,
+		ContentSize:  "medium",
+	}
+
+	if gconv.String(gconv.Map(webSearchOptions)["search_context_size"]) == "high" {
+		webSearch.ContentSize = "high"
+	}
+
+	return append(result, map[string]any{
+		"type":       "web_search",
+		"web_search": webSearch,
+	})
+}
+
 func (z *ZhipuAI) ConvChatCompletionsResponseOfficial(ctx context.Context, response model.ChatCompletionResponse) ([]byte, error) {
 	//TODO implement me
 	panic("implement me")
@@ -59,8 +118,28 @@ func (z *ZhipuAI) ConvChatCompletionsStreamResponseOfficial(ctx context.Context,
 }
 
 func (z *ZhipuAI) ConvImageGenerationsRequestOfficial(ctx context.Context, request model.ImageGenerationRequest) ([]byte, error) {
-	//TODO implement me
-	panic("implement me")
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		logger.Debugf(ctx, "ConvImageGenerationsRequestOfficial time: %d", gtime.TimestampMilli()-now)
+	}()
+
+	imageReq := model.ZhipuAIImageReq{
+		Model:  z.Model,
+		Prompt: request.Prompt,
+		Size:   request.Size,
+		UserId: request.User,
+	}
+
+	// 智谱仅支持hd和standard
+	switch request.Quality {
+	case "hd", "high":
+		imageReq.Quality = "hd"
+	case "standard", "medium", "low":
+		imageReq.Quality = "standard"
+	}
+
+	return gjson.MustEncode(imageReq), nil
 }
 
 func (z *ZhipuAI) ConvImageGenerationsResponseOfficial(ctx context.Context, response model.ImageResponse) ([]byte, error) {
@@ -2,11 +2,71 @@ package zhipuai
 
 import (
 	"context"
+	"slices"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (z *ZhipuAI) TextEmbeddings(ctx context.Context, data []byte) (response model.EmbeddingResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "TextEmbeddings ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "TextEmbeddings ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	var (
+		request        any    = data
+		encodingFormat string // req_data透传时原样返回
+	)
+
+	if !slices.Contains(z.ReqPassthroughParams, "req_data") {
+
+		embeddingReq, err := z.ConvTextEmbeddingsRequest(ctx, data)
+		if err != nil {
+			logger.Errorf(ctx, "TextEmbeddings ZhipuAI ConvTextEmbeddingsRequest error: %v", err)
+			return response, err
+		}
+
+		encodingFormat = embeddingReq.EncodingFormat
+
+		request = model.ZhipuAIEmbeddingReq{
+			Model:      z.Model,
+			Input:      embeddingReq.Input,
+			Dimensions: embeddingReq.Dimensions,
+		}
+	}
+
+	if z.Path == "" {
+		z.Path = "/embeddings"
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "TextEmbeddings ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, responseHeader, err := util.HttpPost(ctx, z.BaseUrl+z.Path, header, request, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "TextEmbeddings ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.convTextEmbeddingsResponse(ctx, bytes, encodingFormat); err != nil {
+		logger.Errorf(ctx, "TextEmbeddings ZhipuAI ConvTextEmbeddingsResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseBytes = bytes
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "TextEmbeddings ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
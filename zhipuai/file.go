@@ -2,31 +2,199 @@ package zhipuai
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (z *ZhipuAI) FileUpload(ctx context.Context, request model.FileUploadRequest) (response model.FileResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "FileUpload ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "FileUpload ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	data, err := z.ConvFileUploadRequest(ctx, request)
+	if err != nil {
+		logger.Errorf(ctx, "FileUpload ZhipuAI ConvFileUploadRequest error: %v", err)
+		return response, err
+	}
+
+	if z.Path == "" {
+		z.Path = "/files"
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "FileUpload ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	responseBytes, responseHeader, err := util.HttpPost(ctx, z.BaseUrl+z.Path, header, data, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "FileUpload ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvFileResponse(ctx, responseBytes); err != nil {
+		logger.Errorf(ctx, "FileUpload ZhipuAI ConvFileResponse error: %v", err)
+		return response, err
+	}
+
+	response.ResponseBytes = responseBytes
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "FileUpload ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
 
 func (z *ZhipuAI) FileList(ctx context.Context, request model.FileListRequest) (response model.FileListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "FileList ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "FileList ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	if z.Path == "" {
+		z.Path = "/files"
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "FileList ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpGet(ctx, z.BaseUrl+z.Path, header, request, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "FileList ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvFileListResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "FileList ZhipuAI ConvFileListResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "FileList ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
 
 func (z *ZhipuAI) FileRetrieve(ctx context.Context, request model.FileRetrieveRequest) (response model.FileResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "FileRetrieve ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "FileRetrieve ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	if z.Path == "" {
+		z.Path = fmt.Sprintf("/files/%s", request.FileId)
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "FileRetrieve ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpGet(ctx, z.BaseUrl+z.Path, header, nil, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "FileRetrieve ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvFileResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "FileRetrieve ZhipuAI ConvFileResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "FileRetrieve ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
 
 func (z *ZhipuAI) FileDelete(ctx context.Context, request model.FileDeleteRequest) (response model.FileResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "FileDelete ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "FileDelete ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	if z.Path == "" {
+		z.Path = fmt.Sprintf("/files/%s", request.FileId)
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "FileDelete ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpDelete(ctx, z.BaseUrl+z.Path, header, nil, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "FileDelete ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvFileResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "FileDelete ZhipuAI ConvFileResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "FileDelete ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
 
 func (z *ZhipuAI) FileContent(ctx context.Context, request model.FileContentRequest) (response model.FileContentResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "FileContent ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "FileContent ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	if z.Path == "" {
+		z.Path = fmt.Sprintf("/files/%s/content", request.FileId)
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "FileContent ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpGet(ctx, z.BaseUrl+z.Path, header, nil, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "FileContent ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvFileContentResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "FileContent ZhipuAI ConvFileContentResponse error: %v", err)
+		return response, err
+	}
+
+	logger.Infof(ctx, "FileContent ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
@@ -2,13 +2,87 @@ package zhipuai
 
 import (
 	"context"
+	"encoding/base64"
+	"slices"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (z *ZhipuAI) ImageGenerations(ctx context.Context, data []byte) (response model.ImageResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "ImageGenerations ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "ImageGenerations ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	// req_data透传时为智谱原生请求, 原样返回图片链接
+	responseFormat := ""
+
+	if !slices.Contains(z.ReqPassthroughParams, "req_data") {
+
+		request, err := z.ConvImageGenerationsRequest(ctx, data)
+		if err != nil {
+			logger.Errorf(ctx, "ImageGenerations ZhipuAI ConvImageGenerationsRequest error: %v", err)
+			return response, err
+		}
+
+		responseFormat = request.ResponseFormat
+
+		if data, err = z.ConvImageGenerationsRequestOfficial(ctx, request); err != nil {
+			logger.Errorf(ctx, "ImageGenerations ZhipuAI ConvImageGenerationsRequestOfficial error: %v", err)
+			return response, err
+		}
+	}
+
+	if z.Path == "" {
+		z.Path = "/images/generations"
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "ImageGenerations ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, responseHeader, err := util.HttpPost(ctx, z.BaseUrl+z.Path, header, data, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "ImageGenerations ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvImageGenerationsResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "ImageGenerations ZhipuAI ConvImageGenerationsResponse error: %v", err)
+		return response, err
+	}
+
+	// 智谱仅返回图片链接, b64_json时下载后编码
+	if responseFormat == "b64_json" {
+		for i, image := range response.Data {
+
+			imageBytes, _, err := util.HttpGet(ctx, image.Url, nil, nil, nil, z.Timeout, z.ProxyUrl, nil)
+			if err != nil {
+				logger.Errorf(ctx, "ImageGenerations ZhipuAI model: %s, download error: %v", z.Model, err)
+				return response, err
+			}
+
+			response.Data[i] = model.ImageResponseData{
+				B64Json: base64.StdEncoding.EncodeToString(imageBytes),
+			}
+		}
+	}
+
+	response.ResponseBytes = bytes
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "ImageGenerations ZhipuAI model: %s finished", z.Model)
+
+	return response, nil
 }
 
 func (z *ZhipuAI) ImageEdits(ctx context.Context, request model.ImageEditRequest) (response model.ImageResponse, err error) {
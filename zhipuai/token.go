@@ -0,0 +1,119 @@
+package zhipuai
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
+)
+
+const (
+	tokenExpiration = 30 * time.Minute // token有效期
+	tokenRefreshAt  = 5 * time.Minute  // 过期前提前刷新, 避免请求过程中过期
+)
+
+// 按key缓存签名后的token, 适配器每次请求都会新建, 需在包级别共享, 新增key时清理已过期未使用的key
+var tokenSources sync.Map
+
+type tokenSource struct {
+	apiKey    string
+	secret    string
+	token     string
+	expiresAt time.Time
+	mutex     sync.RWMutex
+}
+
+// 获取key对应的tokenSource, key格式为: {id}.{secret}, 其它格式返回nil, 直接使用key作为token
+func getTokenSource(key string) *tokenSource {
+
+	if value, ok := tokenSources.Load(key); ok {
+		return value.(*tokenSource)
+	}
+
+	split := strings.Split(key, ".")
+	if len(split) != 2 {
+		return nil
+	}
+
+	evictTokenSources()
+
+	value, _ := tokenSources.LoadOrStore(key, &tokenSource{
+		apiKey: split[0],
+		secret: split[1],
+	})
+
+	return value.(*tokenSource)
+}
+
+// 清理token已过期的key, 过期说明一个有效期内未被使用
+func evictTokenSources() {
+
+	now := time.Now()
+
+	tokenSources.Range(func(key, value any) bool {
+
+		source := value.(*tokenSource)
+
+		source.mutex.RLock()
+		isExpired := source.token != "" && now.After(source.expiresAt)
+		source.mutex.RUnlock()
+
+		if isExpired {
+			tokenSources.CompareAndDelete(key, value)
+		}
+
+		return true
+	})
+}
+
+// Token 返回缓存的token, 即将过期时重新签名, 并发安全
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+
+	s.mutex.RLock()
+	if s.isValid() {
+		defer s.mutex.RUnlock()
+		return s.token, nil
+	}
+	s.mutex.RUnlock()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// 等待锁期间可能已被其它请求刷新
+	if s.isValid() {
+		return s.token, nil
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(tokenExpiration)
+
+	// 智谱要求exp与timestamp均为毫秒级时间戳
+	claims := jwt.MapClaims{
+		"api_key":   s.apiKey,
+		"exp":       expiresAt.UnixMilli(),
+		"timestamp": now.UnixMilli(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	token.Header["alg"] = "HS256"
+	token.Header["sign_type"] = "SIGN"
+
+	sign, err := token.SignedString([]byte(s.secret))
+	if err != nil {
+		logger.Error(ctx, err)
+		return "", err
+	}
+
+	s.token = sign
+	s.expiresAt = expiresAt
+
+	return s.token, nil
+}
+
+func (s *tokenSource) isValid() bool {
+	return s.token != "" && time.Now().Add(tokenRefreshAt).Before(s.expiresAt)
+}
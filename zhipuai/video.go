@@ -2,36 +2,150 @@ package zhipuai
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/iimeta/fastapi-sdk/v2/errors"
+	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
+	"github.com/iimeta/fastapi-sdk/v2/util"
 )
 
 func (z *ZhipuAI) VideoCreate(ctx context.Context, request model.VideoCreateRequest) (response model.VideoJobResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "VideoCreate ZhipuAI model: %s start", z.Model)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "VideoCreate ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	var data any = request
+	if !slices.Contains(z.ReqPassthroughParams, "req_data") {
+		data, err = z.ConvVideoCreateRequest(ctx, request)
+		if err != nil {
+			logger.Errorf(ctx, "VideoCreate ZhipuAI ConvVideoCreateRequest error: %v", err)
+			return response, err
+		}
+	}
+
+	if z.Path == "" {
+		z.Path = "/videos/generations"
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "VideoCreate ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, responseHeader, err := util.HttpPost(ctx, z.BaseUrl+z.Path, header, data, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "VideoCreate ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvVideoJobResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "VideoCreate ZhipuAI ConvVideoJobResponse error: %v", err)
+		return response, err
+	}
+
+	response.CreatedAt = gtime.Timestamp()
+	response.Prompt = request.Prompt
+	response.Seconds = request.Seconds
+	response.Size = request.Size
+	response.ResponseBytes = bytes
+	response.ResponseHeaders = responseHeader
+
+	logger.Infof(ctx, "VideoCreate ZhipuAI model: %s finished, id: %s", z.Model, response.Id)
+
+	return response, nil
 }
 
 func (z *ZhipuAI) VideoRemix(ctx context.Context, request model.VideoRemixRequest) (response model.VideoJobResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("ZhipuAI does not support remixing videos")
 }
 
 func (z *ZhipuAI) VideoList(ctx context.Context, request model.VideoListRequest) (response model.VideoListResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("ZhipuAI does not support listing video tasks")
 }
 
 func (z *ZhipuAI) VideoRetrieve(ctx context.Context, request model.VideoRetrieveRequest) (response model.VideoJobResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "VideoRetrieve ZhipuAI model: %s, videoId: %s start", z.Model, request.VideoId)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "VideoRetrieve ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	if z.Path == "" {
+		z.Path = fmt.Sprintf("/async-result/%s", request.VideoId)
+	}
+
+	header, err := z.getHeader(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "VideoRetrieve ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	bytes, _, err := util.HttpGet(ctx, z.BaseUrl+z.Path, header, nil, nil, z.Timeout, z.ProxyUrl, z.requestErrorHandler)
+	if err != nil {
+		logger.Errorf(ctx, "VideoRetrieve ZhipuAI model: %s, error: %v", z.Model, err)
+		return response, err
+	}
+
+	if response, err = z.ConvVideoJobResponse(ctx, bytes); err != nil {
+		logger.Errorf(ctx, "VideoRetrieve ZhipuAI ConvVideoJobResponse error: %v", err)
+		return response, err
+	}
+
+	// 异步结果不返回任务ID
+	if response.Id == "" {
+		response.Id = request.VideoId
+	}
+
+	logger.Infof(ctx, "VideoRetrieve ZhipuAI model: %s, videoId: %s, status: %s finished", z.Model, request.VideoId, response.Status)
+
+	return response, nil
 }
 
 func (z *ZhipuAI) VideoDelete(ctx context.Context, request model.VideoDeleteRequest) (response model.VideoJobResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+	return response, errors.New("ZhipuAI does not support deleting video tasks")
 }
 
 func (z *ZhipuAI) VideoContent(ctx context.Context, request model.VideoContentRequest) (response model.VideoContentResponse, err error) {
-	//TODO implement me
-	panic("implement me")
+
+	logger.Infof(ctx, "VideoContent ZhipuAI model: %s, videoId: %s start", z.Model, request.VideoId)
+
+	now := gtime.TimestampMilli()
+	defer func() {
+		response.TotalTime = gtime.TimestampMilli() - now
+		logger.Infof(ctx, "VideoContent ZhipuAI model: %s totalTime: %d ms", z.Model, response.TotalTime)
+	}()
+
+	retrieve, err := z.VideoRetrieve(ctx, model.VideoRetrieveRequest{VideoId: request.VideoId})
+	if err != nil {
+		logger.Errorf(ctx, "VideoContent ZhipuAI VideoRetrieve error: %v", err)
+		return response, err
+	}
+
+	if retrieve.VideoUrl == "" {
+		return response, fmt.Errorf("VideoContent ZhipuAI: video_url is empty for videoId %s, status: %s", request.VideoId, retrieve.Status)
+	}
+
+	data, _, err := util.HttpGet(ctx, retrieve.VideoUrl, nil, nil, nil, z.Timeout, z.ProxyUrl, nil)
+	if err != nil {
+		logger.Errorf(ctx, "VideoContent ZhipuAI download error: %v", err)
+		return response, err
+	}
+
+	response = model.VideoContentResponse{Data: data}
+
+	logger.Infof(ctx, "VideoContent ZhipuAI model: %s, videoId: %s finished, size: %d bytes", z.Model, request.VideoId, len(data))
+
+	return response, nil
 }
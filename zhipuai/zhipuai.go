@@ -5,12 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
-	"time"
 
 	"github.com/gogf/gf/v2/encoding/gjson"
-	"github.com/gogf/gf/v2/os/gtime"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/iimeta/fastapi-sdk/v2/errors"
 	"github.com/iimeta/fastapi-sdk/v2/logger"
 	"github.com/iimeta/fastapi-sdk/v2/model"
@@ -32,9 +28,7 @@ func NewAdapter(ctx context.Context, options *options.AdapterOptions) *ZhipuAI {
 		zhipuai.BaseUrl = "https://open.bigmodel.cn/api/paas/v4"
 	}
 
-	zhipuai.header = map[string]string{
-		"Authorization": "Bearer " + zhipuai.generateToken(ctx),
-	}
+	zhipuai.header = make(map[string]string)
 
 	for k, v := range zhipuai.PassthroughHeader {
 		zhipuai.header[k] = v
@@ -49,32 +43,30 @@ func NewAdapter(ctx context.Context, options *options.AdapterOptions) *ZhipuAI {
 	return zhipuai
 }
 
-func (z *ZhipuAI) generateToken(ctx context.Context) string {
+// 每次请求时获取token, 避免长时间使用同一适配器时token过期
+func (z *ZhipuAI) getHeader(ctx context.Context) (map[string]string, error) {
 
-	split := strings.Split(z.Key, ".")
-	if len(split) != 2 {
-		return z.Key
-	}
+	header := make(map[string]string, len(z.header)+1)
 
-	now := gtime.Now()
+	if source := getTokenSource(z.Key); source != nil {
 
-	claims := jwt.MapClaims{
-		"api_key":   split[0],
-		"exp":       now.Add(time.Minute * 10).UnixMilli(),
-		"timestamp": now.UnixMilli(),
-	}
+		token, err := source.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		header["Authorization"] = "Bearer " + token
 
-	token.Header["alg"] = "HS256"
-	token.Header["sign_type"] = "SIGN"
+	} else {
+		header["Authorization"] = "Bearer " + z.Key
+	}
 
-	sign, err := token.SignedString([]byte(split[1]))
-	if err != nil {
-		logger.Error(ctx, err)
+	// 自定义请求头优先
+	for k, v := range z.header {
+		header[k] = v
 	}
 
-	return sign
+	return header, nil
 }
 
 func (z *ZhipuAI) requestErrorHandler(ctx context.Context, response *http.Response) error {